@@ -0,0 +1,90 @@
+package gown
+
+/*
+From wngloss(7WN):
+
+Adjectives are arranged in clusters containing head synsets and satellite
+synsets. Each cluster is organized around antonymous pairs (and occasionally
+antonymous triplets). The antonymous pairs (or triplets) are indicated in the
+head synsets of a cluster. Most head synsets have one or more satellite
+synsets, each of which represents a concept that is similar in meaning to the
+concept represented by the head synset. One way to think of the adjective
+cluster organization is to visualize a wheel, with a head synset as the hub
+and satellite synsets as the spokes. Two or more wheels are logically
+connected via antonymy, which can be thought of as an axle between the
+wheels.
+*/
+
+// Returns the head adjective synset of an adjective satellite, found through
+// its SIMILAR_TO_RELATIONSHIP, or nil if the synset isn't a satellite.
+func (wn *WN) SatelliteHead(synset *Synset) *Synset {
+    if synset == nil || synset.PartOfSpeech != POS_ADJECTIVE_SATELLITE {
+        return nil
+    }
+    for _, similar := range wn.Related(synset, []int { SIMILAR_TO_RELATIONSHIP }) {
+        if similar.PartOfSpeech == POS_ADJECTIVE {
+            return similar
+        }
+    }
+    return nil
+}
+
+// Returns the satellites in the cluster of a head adjective synset, in the
+// order of its edges.
+func (wn *WN) Satellites(head *Synset) []*Synset {
+    ret := []*Synset{}
+    if head == nil || head.PartOfSpeech != POS_ADJECTIVE {
+        return ret
+    }
+    for _, similar := range wn.Related(head, []int { SIMILAR_TO_RELATIONSHIP }) {
+        if similar.PartOfSpeech == POS_ADJECTIVE_SATELLITE {
+            ret = append(ret, similar)
+        }
+    }
+    return ret
+}
+
+// Returns the sense of the head adjective of an adjective satellite sense,
+// found from its HeadWord and HeadId, or nil if it isn't a satellite.
+func (wn *WN) HeadSense(sense *SenseIndexEntry) *SenseIndexEntry {
+    if sense == nil || sense.PartOfSpeech != POS_ADJECTIVE_SATELLITE || sense.HeadWord == "" {
+        return nil
+    }
+    var ret *SenseIndexEntry = nil
+    for _, head := range wn.LookupSensesWithPartOfSpeech(readStoredLemma(sense.HeadWord), POS_ADJECTIVE) {
+        if head.LexId != sense.HeadId {
+            continue
+        }
+        // head_id identifies the head word within the satellite's
+        // lexographer file
+        if head.LexographerFilenum == sense.LexographerFilenum {
+            return head
+        }
+        if ret == nil {
+            ret = head
+        }
+    }
+    return ret
+}
+
+// Returns the indirect antonyms of an adjective synset, as the wn "-antsa"
+// search finds them: a satellite is indirectly antonymous to the antonyms of
+// its head, and a head to the satellites of its antonyms.
+func (wn *WN) IndirectAntonyms(synset *Synset) []*Synset {
+    ret := []*Synset{}
+    if synset == nil {
+        return ret
+    }
+    switch synset.PartOfSpeech {
+    case POS_ADJECTIVE_SATELLITE:
+        head := wn.SatelliteHead(synset)
+        if head != nil {
+            ret = wn.Related(head, []int { ANTONYM_RELATIONSHIP })
+        }
+    case POS_ADJECTIVE:
+        for _, antonym := range wn.Related(synset, []int { ANTONYM_RELATIONSHIP }) {
+            ret = append(ret, wn.Satellites(antonym)...)
+        }
+    }
+    return ret
+}
@@ -0,0 +1,59 @@
+package gown
+
+import (
+    "testing"
+)
+
+func TestAdjectiveClusters(t *testing.T) {
+    contains := func(synsets []*Synset, word string) bool {
+        for _, synset := range synsets {
+            for _, w := range synset.Words {
+                if w == word {
+                    return true
+                }
+            }
+        }
+        return false
+    }
+    forEachLoadMode(t, LoadOptions{}, func(t *testing.T, wn *WN) {
+        aridSense := wn.LookupWithPartOfSpeechAndSense("arid", POS_ADJECTIVE_SATELLITE, 1)
+        if aridSense == nil {
+            t.Fatalf("expected \"arid\" to be an adjective satellite")
+        }
+        arid := aridSense.GetSynsetPtr()
+        dry := wn.SatelliteHead(arid)
+        if dry == nil || dry.Words[0] != "dry" {
+            t.Fatalf("expected the head of arid to be dry, got %v", dry)
+        }
+        if wn.SatelliteHead(dry) != nil {
+            t.Errorf("expected a head adjective to have no head")
+        }
+        if !contains(wn.Satellites(dry), "arid") {
+            t.Errorf("expected arid to be a satellite of dry")
+        }
+
+        headSense := wn.HeadSense(aridSense)
+        if headSense == nil || headSense.SynsetOffset != dry.SynsetOffset {
+            t.Errorf("expected the head sense of arid to be dry, got %v", headSense)
+        }
+        // the sense index agrees with the similar-to pointers
+        for _, sense := range wn.Lookup("live") {
+            if sense.PartOfSpeech != POS_ADJECTIVE_SATELLITE {
+                continue
+            }
+            head := wn.SatelliteHead(sense.GetSynsetPtr())
+            headSense := wn.HeadSense(sense)
+            if head == nil || headSense == nil || head.SynsetOffset != headSense.SynsetOffset {
+                t.Errorf("expected the head of %s to match its similar-to pointer", sense.SenseKey)
+            }
+        }
+
+        if !contains(wn.IndirectAntonyms(arid), "wet") {
+            t.Errorf("expected wet to be an indirect antonym of arid")
+        }
+        wet := wn.LookupWithPartOfSpeechAndSense("wet", POS_ADJECTIVE, 1).GetSynsetPtr()
+        if !contains(wn.IndirectAntonyms(wet), "arid") {
+            t.Errorf("expected arid to be an indirect antonym of wet")
+        }
+    })
+}
@@ -0,0 +1,131 @@
+package gown
+
+import (
+    "fmt"
+    "strings"
+)
+
+/*
+Collocations are lemmas of more than one word, such as "computing machine",
+"give up" and "New York". FindCollocations finds them in running text that
+has already been split into tokens, using a trie of the words of every
+collocation in the index files. Matching is leftmost-longest: at each token
+the longest collocation starting there is taken, and matching resumes after
+it.
+*/
+
+type CollocationMatch struct {
+    Start int               // index of the first token of the match
+    End int                 // index after the last token of the match
+    Lemma string            // e.g. "give up"
+    PartsOfSpeech []int     // the parts of speech the lemma is indexed under
+}
+
+type collocationNode struct {
+    children map[string]*collocationNode
+    lemma string            // set if a collocation ends here
+    partsOfSpeech []int
+}
+
+// Builds the index used by FindCollocations.
+func (wn *WN) BuildCollocationIndex() {
+    root := &collocationNode{}
+    for _, pos := range []int { POS_NOUN, POS_VERB, POS_ADJECTIVE, POS_ADVERB } {
+        pairs := wn.TraverseDataIndex(pos)
+        if pairs == nil {
+            continue
+        }
+        for pair := range pairs {
+            words := strings.Split(pair.Lexeme, " ")
+            if len(words) < 2 {
+                continue
+            }
+            node := root
+            for _, word := range words {
+                if node.children == nil {
+                    node.children = map[string]*collocationNode{}
+                }
+                child, exists := node.children[word]
+                if !exists {
+                    child = &collocationNode{}
+                    node.children[word] = child
+                }
+                node = child
+            }
+            node.lemma = pair.Lexeme
+            if !containsInt(node.partsOfSpeech, pos) {
+                node.partsOfSpeech = append(node.partsOfSpeech, pos)
+            }
+        }
+    }
+
+    wn.collocationIndexLock.Lock()
+    wn.collocationIndex = root
+    wn.collocationIndexLock.Unlock()
+}
+
+// Returns the collocations in a sequence of tokens, in order. With morph,
+// the base forms of the tokens are matched too, (see Morph) so "gave up"
+// matches "give up"; that needs InitMorphData first. Returns an error if
+// BuildCollocationIndex wasn't called.
+func (wn *WN) FindCollocations(tokens []string, morph bool) ([]CollocationMatch, error) {
+    wn.collocationIndexLock.RLock()
+    root := wn.collocationIndex
+    wn.collocationIndexLock.RUnlock()
+    if root == nil {
+        return nil, fmt.Errorf("no collocation index, call BuildCollocationIndex first")
+    }
+
+    // the forms each token may match
+    forms := make([][]string, len(tokens))
+    for i, token := range tokens {
+        forms[i] = wn.collocationForms(token, morph)
+    }
+
+    ret := []CollocationMatch{}
+    for start := 0; start < len(tokens); {
+        var best *collocationNode = nil
+        bestEnd := start
+        var walk func(node *collocationNode, end int)
+        walk = func(node *collocationNode, end int) {
+            if node.lemma != "" && end > bestEnd {
+                best = node
+                bestEnd = end
+            }
+            if end >= len(tokens) {
+                return
+            }
+            for _, form := range forms[end] {
+                child, exists := node.children[form]
+                if exists {
+                    walk(child, end + 1)
+                }
+            }
+        }
+        walk(root, start)
+
+        if best == nil {
+            start++
+            continue
+        }
+        ret = append(ret, CollocationMatch { start, bestEnd, best.lemma, best.partsOfSpeech })
+        start = bestEnd
+    }
+    return ret, nil
+}
+
+// Returns the token in lower case, and with morph its distinct base forms.
+func (wn *WN) collocationForms(token string, morph bool) []string {
+    token = strings.ToLower(token)
+    forms := []string { token }
+    if !morph || len(wn.exceptions) == 0 {
+        return forms
+    }
+    for _, pos := range []int { POS_NOUN, POS_VERB, POS_ADJECTIVE, POS_ADVERB } {
+        form := wn.Morph(token, pos)
+        if form != "" {
+            forms = appendUnique(forms, form)
+        }
+    }
+    return forms
+}
@@ -0,0 +1,45 @@
+package gown
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestFindCollocations(t *testing.T) {
+    tokens := strings.Fields("He gave up the domestic dog to the Attorney General")
+    forEachLoadMode(t, LoadOptions{}, func(t *testing.T, wn *WN) {
+        if _, err := wn.FindCollocations(tokens, false); err == nil {
+            t.Errorf("expected an error without an index")
+        }
+        wn.BuildCollocationIndex()
+
+        matches, err := wn.FindCollocations(tokens, false)
+        if err != nil {
+            t.Fatalf("can't find collocations: %v", err)
+        }
+        expected := []CollocationMatch {
+            { 4, 6, "domestic dog", []int { POS_NOUN } },
+            { 8, 10, "attorney general", []int { POS_NOUN } },
+        }
+        if !reflect.DeepEqual(matches, expected) {
+            t.Errorf("expected %v, got %v", expected, matches)
+        }
+
+        matches, _ = wn.FindCollocations(tokens, true)
+        if len(matches) != 3 || matches[0].Lemma != "give up" || matches[0].Start != 1 || matches[0].End != 3 {
+            t.Errorf("expected \"gave up\" to match \"give up\", got %v", matches)
+        }
+
+        // case doesn't matter
+        matches, _ = wn.FindCollocations([]string { "an", "Aberdeen", "Angus" }, false)
+        if len(matches) != 1 || matches[0].Lemma != "aberdeen angus" || matches[0].Start != 1 {
+            t.Errorf("expected to match \"aberdeen angus\", got %v", matches)
+        }
+
+        matches, _ = wn.FindCollocations([]string{}, true)
+        if len(matches) != 0 {
+            t.Errorf("expected no matches, got %v", matches)
+        }
+    })
+}
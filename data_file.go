@@ -1,10 +1,9 @@
 package gown
 
 import (
-    "bufio"
     "fmt"
     "io"
-    "os"
+    "io/fs"
     "strconv"
     "strings"
 )
@@ -48,8 +47,12 @@ type Synset struct {
     PartOfSpeech int
     Words []string
     LexIds []int
+    Markers []int          // syntactic marker of each word, for adjectives. (e.g. SYNTACTIC_MARKER_PREDICATE_POSITION)
     Relationships []RelationshipEdge
+    Frames []VerbFrame     // sentence frames, only present for verbs
     Gloss string
+    Definition string      // the gloss without its examples
+    Examples []string      // the example sentences of the gloss, without quotes
 }
 type RelationshipEdge struct {
     RelationshipType int      // ANTONYM_RELATIONSHIP, etc.
@@ -58,6 +61,10 @@ type RelationshipEdge struct {
     SourceWordNumber int      // word number of the source
     TargetWordNumber int      // word number of the target
 }
+type VerbFrame struct {
+    FrameNumber int           // index into VERB_FRAME_STRINGS
+    WordNumber int            // word number the frame applies to, or 0 for all words in the synset
+}
 
 type DataIndexPair struct {
     Lexeme string
@@ -80,163 +87,215 @@ func DataIndexIterator(di *dataIndex) <-chan DataIndexPair {
 // Reads a index.POS (e.g. index.noun, index.verb, etc.) file and populates
 // a dataIndex . The index format is:
 // lemma  pos  synset_cnt  p_cnt  [ptr_symbol...]  sense_cnt  tagsense_cnt   synset_offset  [synset_offset...]
-func readPosIndex(posIndexFilename string) (*dataIndex, error) {
-    index := dataIndex{}
-
-    infile, err := os.Open(posIndexFilename)
-    defer infile.Close()
+func readPosIndex(fsys fs.FS, posIndexFilename string) (*dataIndex, error) {
+    infile, err := openDictFile(fsys, posIndexFilename)
     if err != nil {
         return nil, fmt.Errorf("can't open %s: %v", posIndexFilename, err)
     }
-    r := bufio.NewReader(infile)
-    if (r == nil) {
-        return nil, fmt.Errorf("can't read %s: %v" + posIndexFilename, err)
-    }
+    defer infile.Close()
 
-    var readerr error = nil
-    for ; readerr == nil ; {
-        bytebuf, readerr := r.ReadBytes('\n')
-        if readerr != nil && readerr != io.EOF {
-            panic(readerr)
+    index := dataIndex{}
+    lr := newLineReader(infile, posIndexFilename)
+    for {
+        line, err := lr.next()
+        if err == io.EOF {
+            break
         }
-        if len(bytebuf) == 0 {
-            break;
+        if err != nil {
+            return nil, err
         }
-        line := string(bytebuf)
-        if line[0:2] == "  " {
-            // comment line
+        if isCommentLine(line) {
             continue
         }
-        fields := strings.SplitN(strings.TrimSpace(line), " ", -1)
-        lemma := readStoredLemma(fields[0])
-        pos_tag := oneCharPosTagToPosId(fields[1])
-        synset_cnt, _ := strconv.Atoi(fields[2])     // number of senses of the <lemma, pos> pair
-        p_cnt, _ := strconv.Atoi(fields[3])          // number of different pointers that lemma has in all synsets containing it.
-        field_index := 4
-        relationships := make([]int, p_cnt)
-        // consume p_cnt pointer symbols
-        for i := 0; i < p_cnt; i++ {
-            relationships[i], _ = RELATIONSHIP_POINTER_SYMBOLS[fields[field_index]]
-            field_index++
-        }
-        field_index++  // sense_cnt is redundant with synset_cnt, so skip it
-        tagsense_cnt, _ := strconv.Atoi(fields[field_index])
-        field_index++
-        synsetOffsets := make([]int, synset_cnt)
-        for i := 0; i < synset_cnt; i++ {
-            synsetOffsets[i], _ = strconv.Atoi(fields[field_index])
-            field_index++
+        lemma, entry, err := parsePosIndexLine(lr.fields(line))
+        if err != nil {
+            return nil, err
         }
 
         _, exists := index[lemma]
         if exists {
-            fmt.Printf("WARNING: %s already exists. Overwriting.\n", lemma)
-        }
-        index[lemma] = DataIndexEntry {
-            PartOfSpeech: pos_tag,
-            SynsetCount: synset_cnt,
-            Relationships: relationships,
-            TagSenseCount: tagsense_cnt,
-            SynsetOffsets: synsetOffsets,
+            // the index is sorted with one line per lemma
+            err := lr.fieldError("lemma", lineKey(line))
+            err.Err = ErrDuplicateLemma
+            return nil, err
         }
+        index[lemma] = entry
     }
 
     return &index, nil
 }
 
+// Parses one line of an index.POS file.
+func parsePosIndexLine(f *fieldReader) (string, DataIndexEntry, error) {
+    lemma := readStoredLemma(f.str("lemma"))
+    pos_tag := oneCharPosTagToPosId(f.str("pos"))
+    synset_cnt := f.decimal("synset_cnt")  // number of senses of the <lemma, pos> pair
+    p_cnt := f.decimal("p_cnt")            // number of different pointers that lemma has in all synsets containing it.
+    if p_cnt < 0 || synset_cnt < 0 {
+        f.fail("p_cnt", strconv.Itoa(p_cnt), ErrMalformedField)
+    }
+    relationships := []int{}
+    // consume p_cnt pointer symbols
+    for i := 0; i < p_cnt && f.err == nil; i++ {
+        relationships = append(relationships, f.pointerSymbol("ptr_symbol"))
+    }
+    f.decimal("sense_cnt")  // sense_cnt is redundant with synset_cnt, so skip it
+    tagsense_cnt := f.decimal("tagsense_cnt")
+    synsetOffsets := []int{}
+    for i := 0; i < synset_cnt && f.err == nil; i++ {
+        synsetOffsets = append(synsetOffsets, f.decimal("synset_offset"))
+    }
+    if f.err != nil {
+        return "", DataIndexEntry{}, f.err
+    }
+
+    return lemma, DataIndexEntry {
+        PartOfSpeech: pos_tag,
+        SynsetCount: synset_cnt,
+        Relationships: relationships,
+        TagSenseCount: tagsense_cnt,
+        SynsetOffsets: synsetOffsets,
+    }, nil
+}
+
 // Reads a data.POS (e.g. data.noun, data.verb, etc.) file and populates
 // a map of ints to dataIndexEntries. The data format is:
 // synset_offset  lex_filenum  ss_type  w_cnt  word  lex_id  [word  lex_id...]  p_cnt  [ptr...]  [frames...]  |   gloss
-func readPosData(posDataFilename string) (*dataFile, error) {
-    data := dataFile{}
-
-    infile, err := os.Open(posDataFilename)
-    defer infile.Close()
+func readPosData(fsys fs.FS, posDataFilename string) (*dataFile, error) {
+    infile, err := openDictFile(fsys, posDataFilename)
     if err != nil {
         return nil, fmt.Errorf("can't open %s: %v", posDataFilename, err)
     }
-    r := bufio.NewReader(infile)
-    if (r == nil) {
-        return nil, fmt.Errorf("can't read %s: %v" + posDataFilename, err)
-    }
+    defer infile.Close()
 
-    var readerr error = nil
-    for ; readerr == nil ; {
-        bytebuf, readerr := r.ReadBytes('\n')
-        if readerr != nil && readerr != io.EOF {
-            panic(readerr)
+    data := dataFile{}
+    lr := newLineReader(infile, posDataFilename)
+    for {
+        line, err := lr.next()
+        if err == io.EOF {
+            break
         }
-        if len(bytebuf) == 0 {
-            break;
+        if err != nil {
+            return nil, err
         }
-        line := string(bytebuf)
-        if line[0:2] == "  " {
-            // comment line
+        if isCommentLine(line) {
             continue
         }
-        fields := strings.SplitN(strings.TrimSpace(line), " ", -1)
-        synset_offset, _ := strconv.Atoi(fields[0])
-        lex_filenum, _ := strconv.Atoi(fields[1])
-        ss_type := oneCharPosTagToPosId(fields[2])
-        w_cnt64, _ := strconv.ParseInt(fields[3], 16, 0)
-        w_cnt := int(w_cnt64)
-        words := make([]string, w_cnt)
-        lex_ids := make([]int, w_cnt)
-        fieldIndex := 4
-        for i := 0; i < w_cnt; i++ {
-            words[i] = readStoredLemma(fields[fieldIndex])
-            fieldIndex++
-            lex_id64, _ := strconv.ParseInt(fields[fieldIndex], 16, 0)
-            lex_ids[i] = int(lex_id64)
-            fieldIndex++
+        synset, err := parsePosDataLine(posDataFilename, lr.lineNumber, line)
+        if err != nil {
+            return nil, err
         }
-        p_cnt, _ := strconv.Atoi(fields[fieldIndex])
-        fieldIndex++
-        pointers := make([]RelationshipEdge, p_cnt)
-        for i := 0; i < p_cnt; i++ {
-            pointer_type, symbolFound := RELATIONSHIP_POINTER_SYMBOLS[fields[fieldIndex]]
-            if !symbolFound {
-                panic(fmt.Sprintf("could not handle relationship symbol %s in line <<%v>>, file %s", fields[fieldIndex], line, posDataFilename))
-            }
-            fieldIndex++
-            synset_offset, _ := strconv.Atoi(fields[fieldIndex])
-            fieldIndex++
-            pos := oneCharPosTagToPosId(fields[fieldIndex])
-            fieldIndex++
-
-            src_wordnum64, _ := strconv.ParseInt(fields[fieldIndex][0:2], 16, 0)
-            dest_wordnum64, _ := strconv.ParseInt(fields[fieldIndex][2:4], 16, 0)
-            fieldIndex++
-            src_word_num := int(src_wordnum64)
-            dest_word_num := int(dest_wordnum64)
-            pointers[i] = RelationshipEdge {
-                RelationshipType: pointer_type,
-                SynsetOffset: synset_offset,
-                PartOfSpeech: pos,
-                SourceWordNumber: src_word_num,
-                TargetWordNumber: dest_word_num,
-            }
+        data[synset.SynsetOffset] = synset
+    }
+
+    return &data, nil
+}
+
+// Parses one line of a data.POS file. lineNumber is only used for error
+// reporting.
+func parsePosDataLine(posDataFilename string, lineNumber int, line string) (Synset, error) {
+    // pointer symbols and words never contain a "|", so the first one
+    // starts the gloss
+    pipeIndex := strings.Index(line, "|")
+    var gloss string
+    if pipeIndex >= 0 {
+        gloss = strings.TrimSpace(line[pipeIndex + 1:])
+        line = line[:pipeIndex]
+    } else {
+        gloss = ""
+    }
+
+    f := newFieldReader(posDataFilename, lineNumber, line)
+    synset_offset := f.decimal("synset_offset")
+    lex_filenum := f.decimal("lex_filenum")
+    ss_type := oneCharPosTagToPosId(f.str("ss_type"))
+    w_cnt := f.hex("w_cnt")
+    words := []string{}
+    lex_ids := []int{}
+    markers := []int{}
+    for i := 0; i < w_cnt && f.err == nil; i++ {
+        word, marker := readStoredWord(f.str("word"))
+        words = append(words, word)
+        lex_ids = append(lex_ids, f.hex("lex_id"))
+        markers = append(markers, marker)
+    }
+    p_cnt := f.decimal("p_cnt")
+    pointers := []RelationshipEdge{}
+    for i := 0; i < p_cnt && f.err == nil; i++ {
+        pointer_type := f.pointerSymbol("pointer_symbol")
+        synset_offset := f.decimal("synset_offset")
+        pos := oneCharPosTagToPosId(f.str("pos"))
+        source_target := f.str("source/target")
+        if f.err == nil && len(source_target) != 4 {
+            f.fail("source/target", source_target, ErrMalformedField)
         }
-        // skip data.verb frames
-
-        pipeIndex := strings.LastIndex(line, "|")
-        var gloss string
-        if pipeIndex >= 0 {
-            gloss = strings.TrimSpace(line[pipeIndex + 2:])
-        } else {
-            gloss = ""
+        if f.err != nil {
+            break
         }
 
-        data[synset_offset] = Synset {
-                SynsetOffset: synset_offset,
-                LexographerFilenum: lex_filenum,
-                PartOfSpeech: ss_type,
-                Words: words,
-                LexIds: lex_ids,
-                Relationships: pointers,
-                Gloss: gloss,
+        src_wordnum64, err1 := strconv.ParseInt(source_target[0:2], 16, 0)
+        dest_wordnum64, err2 := strconv.ParseInt(source_target[2:4], 16, 0)
+        if err1 != nil || err2 != nil {
+            f.fail("source/target", source_target, ErrMalformedField)
         }
+        src_word_num := int(src_wordnum64)
+        dest_word_num := int(dest_wordnum64)
+        pointers = append(pointers, RelationshipEdge {
+            RelationshipType: pointer_type,
+            SynsetOffset: synset_offset,
+            PartOfSpeech: pos,
+            SourceWordNumber: src_word_num,
+            TargetWordNumber: dest_word_num,
+        })
     }
+    // data.verb frames: f_cnt  +  f_num  w_num  [+  f_num  w_num...]
+    var frames []VerbFrame = nil
+    if ss_type == POS_VERB && f.more() {
+        f_cnt := f.decimal("f_cnt")
+        frames = []VerbFrame{}
+        for i := 0; i < f_cnt && f.err == nil; i++ {
+            f.str("+")
+            f_num := f.decimal("f_num")
+            w_num := f.hex("w_num")
+            frames = append(frames, VerbFrame {
+                FrameNumber: f_num,
+                WordNumber: w_num,
+            })
+        }
+    }
+    if f.err != nil {
+        return Synset{}, f.err
+    }
+    definition, examples := parseGloss(gloss)
 
-    return &data, nil
+    return Synset {
+            SynsetOffset: synset_offset,
+            LexographerFilenum: lex_filenum,
+            PartOfSpeech: ss_type,
+            Words: words,
+            LexIds: lex_ids,
+            Markers: markers,
+            Relationships: pointers,
+            Frames: frames,
+            Gloss: gloss,
+            Definition: definition,
+            Examples: examples,
+    }, nil
+}
+
+// Returns the index of a word of the synset, matched case insensitively,
+// or -1 if it isn't in the synset.
+func (s *Synset) wordIndex(lemma string) int {
+    for i, word := range s.Words {
+        if strings.EqualFold(word, lemma) {
+            return i
+        }
+    }
+    return -1
+}
+
+// the license at the top of the index and data files is indented by two
+// spaces
+func isCommentLine(line string) bool {
+    return strings.HasPrefix(line, "  ") || len(line) == 0
 }
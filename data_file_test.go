@@ -1,6 +1,9 @@
 package gown
 
 import (
+    "errors"
+    "os"
+    "path/filepath"
     "testing"
 )
 
@@ -11,8 +14,8 @@ var (
 func TestLoadPosIndex(t *testing.T) {
     dictDir, _ := GetWordNetDictDir()
     for _, posName := range POS_FILE_NAMES {
-        posIndexFilename := dictDir + "/index."  + posName
-        _, err := readPosIndex(posIndexFilename)
+        posIndexFilename := "index."  + posName
+        _, err := readPosIndex(os.DirFS(dictDir), posIndexFilename)
         if err != nil {
             t.Fatalf("failed to read %s: %v", posIndexFilename, err)
         }
@@ -22,10 +25,91 @@ func TestLoadPosIndex(t *testing.T) {
 func TestLoadPosData(t *testing.T) {
     dictDir, _ := GetWordNetDictDir()
     for _, posName := range POS_FILE_NAMES {
-        posDataFilename := dictDir + "/data."  + posName
-        _, err := readPosData(posDataFilename)
+        posDataFilename := "data."  + posName
+        _, err := readPosData(os.DirFS(dictDir), posDataFilename)
         if err != nil {
             t.Fatalf("failed to read %s: %v", posDataFilename, err)
         }
     }
 }
+
+func TestLoadPosDataParseError(t *testing.T) {
+    dir := t.TempDir()
+    posDataFilename := filepath.Join(dir, "data.noun")
+    lines := "  1 This software and database is being provided to you, the LICENSEE, by\n" +
+        "00001740 03 n 01 entity 0 001 ?? 00001930 n 0000 | that which is perceived\n"
+    err := os.WriteFile(posDataFilename, []byte(lines), 0644)
+    if err != nil {
+        t.Fatalf("can't write %s: %v", posDataFilename, err)
+    }
+
+    _, err = readPosData(os.DirFS(dir), "data.noun")
+    var parseErr *ParseError
+    if !errors.As(err, &parseErr) {
+        t.Fatalf("expected a *ParseError, but got %v", err)
+    }
+    if parseErr.Line != 2 || parseErr.Field != "pointer_symbol" || parseErr.Value != "??" {
+        t.Errorf("unexpected error %v", parseErr)
+    }
+    if !errors.Is(err, ErrUnknownPointerSymbol) {
+        t.Errorf("expected ErrUnknownPointerSymbol, but got %v", parseErr.Err)
+    }
+}
+
+func TestLoadPosIndexDuplicateLemma(t *testing.T) {
+    dir := t.TempDir()
+    lines := "dog n 1 1 @ 1 1 02084071  \n" +
+        "dog n 1 1 @ 1 0 02084072  \n"
+    err := os.WriteFile(filepath.Join(dir, "index.noun"), []byte(lines), 0644)
+    if err != nil {
+        t.Fatalf("can't write index.noun: %v", err)
+    }
+
+    _, err = readPosIndex(os.DirFS(dir), "index.noun")
+    var parseErr *ParseError
+    if !errors.As(err, &parseErr) {
+        t.Fatalf("expected a *ParseError, but got %v", err)
+    }
+    if parseErr.Line != 2 || parseErr.Field != "lemma" || parseErr.Value != "dog" {
+        t.Errorf("unexpected error %v", parseErr)
+    }
+    if !errors.Is(err, ErrDuplicateLemma) {
+        t.Errorf("expected ErrDuplicateLemma, but got %v", parseErr.Err)
+    }
+}
+
+func TestParseSyntacticMarkers(t *testing.T) {
+    line := "01234567 00 s 03 elect(ip) 0 chosen(p) 1 big_deal(a) 0 000 | elected but not yet installed in office  "
+    synset, err := parsePosDataLine("data.adj", 1, line)
+    if err != nil {
+        t.Fatalf("can't parse %q: %v", line, err)
+    }
+    expectedWords := []string { "elect", "chosen", "big deal" }
+    expectedMarkers := []int {
+        SYNTACTIC_MARKER_IMMEDIATELY_POSTNOMIAL_POSITION,
+        SYNTACTIC_MARKER_PREDICATE_POSITION,
+        SYNTACTIC_MARKER_PRENOMINAL_POSITION,
+    }
+    for i, _ := range expectedWords {
+        if synset.Words[i] != expectedWords[i] || synset.Markers[i] != expectedMarkers[i] {
+            t.Errorf("expected word %d to be %q with marker %d, got %q with marker %d",
+                i, expectedWords[i], expectedMarkers[i], synset.Words[i], synset.Markers[i])
+        }
+    }
+
+    wn := loadTestWordNet(t, LoadOptions{})
+    found := false
+    for _, sense := range wn.Lookup("elect") {
+        if sense.SyntacticMarker == SYNTACTIC_MARKER_IMMEDIATELY_POSTNOMIAL_POSITION {
+            found = true
+        }
+    }
+    if !found {
+        t.Errorf("expected a sense of \"elect\" to be immediately postnominal")
+    }
+    for _, sense := range wn.Lookup("dog") {
+        if sense.SyntacticMarker != SYNTACTIC_MARKER_NOT_APPLICABLE {
+            t.Errorf("expected no syntactic marker for %s", sense.SenseKey)
+        }
+    }
+}
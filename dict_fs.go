@@ -0,0 +1,61 @@
+package gown
+
+import (
+    "compress/gzip"
+    "errors"
+    "io"
+    "io/fs"
+)
+
+/*
+The dictionary files are read through an fs.FS so the same loaders work for
+a directory on disk (os.DirFS), dictionaries embedded with go:embed, archives
+(e.g. zip.Reader) and test fixtures. Any file may also be stored gzip
+compressed with a ".gz" suffix (e.g. data.noun.gz); it is decompressed
+transparently.
+*/
+
+// Opens a dictionary file, falling back to a gzip compressed copy of it.
+func openDictFile(fsys fs.FS, name string) (io.ReadCloser, error) {
+    infile, err := fsys.Open(name)
+    if err == nil {
+        return infile, nil
+    }
+    if !errors.Is(err, fs.ErrNotExist) {
+        return nil, err
+    }
+
+    gzfile, gzerr := fsys.Open(name + ".gz")
+    if gzerr != nil {
+        // report the uncompressed name
+        return nil, err
+    }
+    gzreader, gzerr := gzip.NewReader(gzfile)
+    if gzerr != nil {
+        gzfile.Close()
+        return nil, gzerr
+    }
+    return &gzipFile { gzreader, gzfile }, nil
+}
+
+// true if the file, or a gzip compressed copy of it, exists
+func dictFileExists(fsys fs.FS, name string) bool {
+    if _, err := fs.Stat(fsys, name); err == nil {
+        return true
+    }
+    _, err := fs.Stat(fsys, name + ".gz")
+    return err == nil
+}
+
+type gzipFile struct {
+    *gzip.Reader
+    file fs.File
+}
+
+func (g *gzipFile) Close() error {
+    err := g.Reader.Close()
+    if ferr := g.file.Close(); err == nil {
+        err = ferr
+    }
+    return err
+}
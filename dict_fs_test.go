@@ -0,0 +1,44 @@
+package gown
+
+import (
+    "bytes"
+    "compress/gzip"
+    "os"
+    "testing"
+    "testing/fstest"
+)
+
+func TestLoadWordNetFS(t *testing.T) {
+    wn, err := LoadWordNetFS(os.DirFS(TEST_DICT_DIR))
+    if err != nil {
+        t.Fatalf("can't load WordNet from %s: %v", TEST_DICT_DIR, err)
+    }
+    if wn.LookupWithPartOfSpeech("computer", POS_NOUN) == nil {
+        t.Errorf("\"computer\" not found. Not loaded correctly?")
+    }
+    err = wn.InitMorphDataFS(os.DirFS(TEST_DICT_DIR))
+    if err != nil {
+        t.Fatalf("failed to load morph data: %v", err)
+    }
+}
+
+func TestOpenGzipDictFile(t *testing.T) {
+    var compressed bytes.Buffer
+    w := gzip.NewWriter(&compressed)
+    w.Write([]byte("1 The children %s to the playground\n2 The banks %s the check\n"))
+    w.Close()
+    fsys := fstest.MapFS {
+        "sents.vrb.gz": &fstest.MapFile { Data: compressed.Bytes() },
+    }
+
+    if !dictFileExists(fsys, "sents.vrb") {
+        t.Fatalf("expected sents.vrb to be found compressed")
+    }
+    sentences, err := loadNumberedLines(fsys, "sents.vrb")
+    if err != nil {
+        t.Fatalf("failed to read sents.vrb.gz: %v", err)
+    }
+    if sentences[2] != "The banks %s the check" {
+        t.Errorf("unexpected sentences %v", sentences)
+    }
+}
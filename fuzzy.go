@@ -0,0 +1,290 @@
+package gown
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+    "unicode"
+)
+
+/*
+Fuzzy lookup finds the lemmas of the sense index within an edit distance of
+a misspelled word, such as "computr" or "acomodate". The distance is the
+Damerau-Levenshtein distance: the number of characters inserted, deleted,
+substituted or swapped with their neighbor. The lemmas are kept in a BK-tree,
+which only has to compare the word with a small part of the vocabulary.
+Lemmas that sound like the word (by their Soundex codes) can be included
+too.
+*/
+
+type FuzzyOptions struct {
+    MaxDistance int         // the largest edit distance matched. (0 matches the word exactly)
+    Phonetic bool           // also match lemmas with the same Soundex code, at any distance
+    Limit int               // the maximum number of matches, or no limit if 0
+}
+
+type FuzzyMatch struct {
+    Lemma string
+    Distance int            // the edit distance from the word
+    Phonetic bool           // true if the lemma sounds like the word
+    TagCount int            // the total TagCount of the senses of the lemma
+}
+
+type fuzzyIndex struct {
+    nodes []bkNode
+    tagCounts map[string]int
+    soundex map[string][]string
+}
+
+type bkNode struct {
+    lemma []rune
+    children []bkEdge
+}
+
+type bkEdge struct {
+    distance int
+    node int32
+}
+
+// Builds the index used by FuzzyLookup.
+func (wn *WN) BuildFuzzyIndex() {
+    index := &fuzzyIndex {
+        nodes: []bkNode{},
+        tagCounts: map[string]int{},
+        soundex: map[string][]string{},
+    }
+    for sense := range wn.IterSenses() {
+        index.tagCounts[sense.Lemma] += sense.TagCount
+    }
+    lemmas := make([]string, 0, len(index.tagCounts))
+    for lemma, _ := range index.tagCounts {
+        lemmas = append(lemmas, lemma)
+    }
+    // a fixed order gives the same tree every time
+    sort.Strings(lemmas)
+    for _, lemma := range lemmas {
+        index.add(lemma)
+        code := soundex(lemma)
+        if code != "" {
+            index.soundex[code] = append(index.soundex[code], lemma)
+        }
+    }
+
+    wn.fuzzyIndexLock.Lock()
+    wn.fuzzyIndex = index
+    wn.fuzzyIndexLock.Unlock()
+}
+
+// Returns the lemmas within opts.MaxDistance edits of the word, and with
+// opts.Phonetic the ones that sound like it, closest first and then by how
+// often they're tagged. Returns an error if BuildFuzzyIndex wasn't called.
+func (wn *WN) FuzzyLookup(word string, opts FuzzyOptions) ([]FuzzyMatch, error) {
+    wn.fuzzyIndexLock.RLock()
+    index := wn.fuzzyIndex
+    wn.fuzzyIndexLock.RUnlock()
+    if index == nil {
+        return nil, fmt.Errorf("no fuzzy index, call BuildFuzzyIndex first")
+    }
+
+    word = strings.ToLower(readStoredLemma(word))
+    matches := map[string]*FuzzyMatch{}
+    index.search([]rune(word), opts.MaxDistance, func(lemma string, distance int) {
+        matches[lemma] = &FuzzyMatch { lemma, distance, false, index.tagCounts[lemma] }
+    })
+    if opts.Phonetic {
+        code := soundex(word)
+        for _, lemma := range index.soundex[code] {
+            match, exists := matches[lemma]
+            if !exists {
+                match = &FuzzyMatch { lemma, editDistance([]rune(word), []rune(lemma)), false, index.tagCounts[lemma] }
+                matches[lemma] = match
+            }
+            match.Phonetic = true
+        }
+    }
+
+    ret := make([]FuzzyMatch, 0, len(matches))
+    for _, match := range matches {
+        ret = append(ret, *match)
+    }
+    sort.Slice(ret, func(i, j int) bool {
+        if ret[i].Distance != ret[j].Distance {
+            return ret[i].Distance < ret[j].Distance
+        }
+        if ret[i].TagCount != ret[j].TagCount {
+            return ret[i].TagCount > ret[j].TagCount
+        }
+        return ret[i].Lemma < ret[j].Lemma
+    })
+    if opts.Limit > 0 && len(ret) > opts.Limit {
+        ret = ret[:opts.Limit]
+    }
+    return ret, nil
+}
+
+func (index *fuzzyIndex) add(lemma string) {
+    runes := []rune(lemma)
+    if len(index.nodes) == 0 {
+        index.nodes = append(index.nodes, bkNode { runes, nil })
+        return
+    }
+    current := int32(0)
+    for {
+        distance := editDistance(runes, index.nodes[current].lemma)
+        if distance == 0 {
+            return
+        }
+        next := int32(-1)
+        for _, edge := range index.nodes[current].children {
+            if edge.distance == distance {
+                next = edge.node
+                break
+            }
+        }
+        if next < 0 {
+            index.nodes = append(index.nodes, bkNode { runes, nil })
+            index.nodes[current].children = append(index.nodes[current].children, bkEdge { distance, int32(len(index.nodes) - 1) })
+            return
+        }
+        current = next
+    }
+}
+
+// Calls found with every lemma within maxDistance of the word.
+func (index *fuzzyIndex) search(word []rune, maxDistance int, found func(lemma string, distance int)) {
+    if len(index.nodes) == 0 {
+        return
+    }
+    stack := []int32 { 0 }
+    for len(stack) > 0 {
+        node := &index.nodes[stack[len(stack) - 1]]
+        stack = stack[:len(stack) - 1]
+        distance := editDistance(word, node.lemma)
+        if distance <= maxDistance {
+            found(string(node.lemma), distance)
+        }
+        // by the triangle inequality, only these children can be close
+        for _, edge := range node.children {
+            if edge.distance >= distance - maxDistance && edge.distance <= distance + maxDistance {
+                stack = append(stack, edge.node)
+            }
+        }
+    }
+}
+
+// Returns the Damerau-Levenshtein distance between a and b, allowing
+// transpositions of characters that aren't adjacent after other edits.
+// Unlike the restricted (optimal string alignment) distance, this is a
+// metric, which the BK-tree needs.
+func editDistance(a []rune, b []rune) int {
+    infinity := len(a) + len(b)
+    width := len(b) + 2
+    // lemmas are short, so the table usually fits on the stack
+    var buffer [1024]int
+    var d []int
+    if (len(a) + 2) * width <= len(buffer) {
+        d = buffer[:(len(a) + 2) * width]
+    } else {
+        d = make([]int, (len(a) + 2) * width)
+    }
+    d[0] = infinity
+    for i := 0; i <= len(a); i++ {
+        d[(i + 1) * width] = infinity
+        d[(i + 1) * width + 1] = i
+    }
+    for j := 0; j <= len(b); j++ {
+        d[j + 1] = infinity
+        d[width + j + 1] = j
+    }
+    // the last row each character was seen in
+    var asciiLastRow [128]int
+    var lastRow map[rune]int = nil
+    getLastRow := func(r rune) int {
+        if r < 128 {
+            return asciiLastRow[r]
+        }
+        return lastRow[r]
+    }
+    for i := 1; i <= len(a); i++ {
+        lastMatchingColumn := 0
+        for j := 1; j <= len(b); j++ {
+            i1 := getLastRow(b[j - 1])
+            j1 := lastMatchingColumn
+            cost := 1
+            if a[i - 1] == b[j - 1] {
+                cost = 0
+                lastMatchingColumn = j
+            }
+            distance := d[i * width + j] + cost                         // substitution
+            distance = minInt(distance, d[(i + 1) * width + j] + 1)     // insertion
+            distance = minInt(distance, d[i * width + j + 1] + 1)       // deletion
+            distance = minInt(distance, d[i1 * width + j1] + (i - i1 - 1) + 1 + (j - j1 - 1)) // transposition
+            d[(i + 1) * width + j + 1] = distance
+        }
+        if a[i - 1] < 128 {
+            asciiLastRow[a[i - 1]] = i
+        } else {
+            if lastRow == nil {
+                lastRow = map[rune]int{}
+            }
+            lastRow[a[i - 1]] = i
+        }
+    }
+    return d[(len(a) + 1) * width + len(b) + 1]
+}
+
+func minInt(a int, b int) int {
+    if a < b {
+        return a
+    }
+    return b
+}
+
+var SOUNDEX_CODES = map[rune]byte {
+    'b': '1', 'f': '1', 'p': '1', 'v': '1',
+    'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 's': '2', 'x': '2', 'z': '2',
+    'd': '3', 't': '3',
+    'l': '4',
+    'm': '5', 'n': '5',
+    'r': '6',
+}
+
+// Returns the American Soundex code of a word, (e.g. "R163" for "Robert"
+// and "Rupert") ignoring anything but the letters a-z. Returns "" if there
+// are none.
+func soundex(word string) string {
+    code := []byte{}
+    var last byte = 0
+    for _, r := range strings.ToLower(word) {
+        if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+            continue
+        }
+        digit, isConsonant := SOUNDEX_CODES[r]
+        if len(code) == 0 {
+            code = append(code, byte(unicode.ToUpper(r)))
+            last = digit
+            continue
+        }
+        if !isConsonant {
+            if r != 'h' && r != 'w' {
+                // a vowel separates consonants with the same code
+                last = 0
+            }
+            continue
+        }
+        if digit != last {
+            code = append(code, digit)
+            if len(code) == 4 {
+                break
+            }
+        }
+        last = digit
+    }
+    if len(code) == 0 {
+        return ""
+    }
+    for len(code) < 4 {
+        code = append(code, '0')
+    }
+    return string(code)
+}
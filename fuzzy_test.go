@@ -0,0 +1,113 @@
+package gown
+
+import (
+    "testing"
+)
+
+func TestEditDistance(t *testing.T) {
+    tests := []struct {
+        a string
+        b string
+        expected int
+    } {
+        { "", "", 0 },
+        { "dog", "dog", 0 },
+        { "dog", "", 3 },
+        { "computr", "computer", 1 },
+        { "dgo", "dog", 1 },              // transposition
+        { "acomodate", "accommodate", 2 },
+        { "ca", "abc", 2 },               // 3 with the restricted distance
+        { "kitten", "sitting", 3 },
+    }
+    for _, test := range tests {
+        for _, pair := range [][]string { { test.a, test.b }, { test.b, test.a } } {
+            distance := editDistance([]rune(pair[0]), []rune(pair[1]))
+            if distance != test.expected {
+                t.Errorf("expected the distance from %q to %q to be %d, got %d", pair[0], pair[1], test.expected, distance)
+            }
+        }
+    }
+}
+
+func TestSoundex(t *testing.T) {
+    tests := map[string]string {
+        "Robert": "R163",
+        "Rupert": "R163",
+        "Ashcraft": "A261",
+        "Tymczak": "T522",
+        "Pfister": "P236",
+        "dog": "D200",
+        "dawg": "D200",
+        "": "",
+    }
+    for word, expected := range tests {
+        if code := soundex(word); code != expected {
+            t.Errorf("expected the soundex code of %q to be %q, got %q", word, expected, code)
+        }
+    }
+}
+
+func TestFuzzyLookup(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    if _, err := wn.FuzzyLookup("computr", FuzzyOptions { MaxDistance: 1 }); err == nil {
+        t.Errorf("expected an error without an index")
+    }
+    wn.BuildFuzzyIndex()
+
+    find := func(matches []FuzzyMatch, lemma string) *FuzzyMatch {
+        for i, _ := range matches {
+            if matches[i].Lemma == lemma {
+                return &matches[i]
+            }
+        }
+        return nil
+    }
+
+    matches, _ := wn.FuzzyLookup("Computr", FuzzyOptions { MaxDistance: 1 })
+    computer := find(matches, "computer")
+    if computer == nil || computer.Distance != 1 {
+        t.Errorf("expected \"computr\" to find \"computer\", got %v", matches)
+    }
+    for i, match := range matches {
+        if match.Distance > 1 {
+            t.Errorf("unexpected match %v", match)
+        }
+        if i > 0 && (match.Distance < matches[i - 1].Distance ||
+            (match.Distance == matches[i - 1].Distance && match.TagCount > matches[i - 1].TagCount)) {
+            t.Errorf("expected the matches to be ranked, got %v", matches)
+        }
+    }
+
+    matches, _ = wn.FuzzyLookup("dog", FuzzyOptions { MaxDistance: 0 })
+    if len(matches) != 1 || matches[0].Lemma != "dog" || matches[0].TagCount == 0 {
+        t.Errorf("expected an exact match for \"dog\", got %v", matches)
+    }
+    matches, _ = wn.FuzzyLookup("dgo", FuzzyOptions { MaxDistance: 1 })
+    if find(matches, "dog") == nil {
+        t.Errorf("expected \"dgo\" to find \"dog\", got %v", matches)
+    }
+
+    matches, _ = wn.FuzzyLookup("dawg", FuzzyOptions { MaxDistance: 1 })
+    if find(matches, "dog") != nil {
+        t.Errorf("expected \"dawg\" not to be within 1 edit of \"dog\"")
+    }
+    matches, _ = wn.FuzzyLookup("dawg", FuzzyOptions { MaxDistance: 1, Phonetic: true })
+    dog := find(matches, "dog")
+    if dog == nil || !dog.Phonetic || dog.Distance != 2 {
+        t.Errorf("expected \"dawg\" to sound like \"dog\", got %v", matches)
+    }
+
+    matches, _ = wn.FuzzyLookup("dog", FuzzyOptions { MaxDistance: 3, Limit: 2 })
+    if len(matches) != 2 {
+        t.Errorf("expected 2 matches, got %d", len(matches))
+    }
+}
+
+func BenchmarkFuzzyLookup(b *testing.B) {
+    wn, _ := loadSystemWordNet(b)
+    wn.BuildFuzzyIndex()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        wn.FuzzyLookup("acomodate", FuzzyOptions { MaxDistance: 2 })
+    }
+}
@@ -0,0 +1,267 @@
+package gown
+
+import (
+    "encoding/xml"
+    "fmt"
+    "io"
+    "io/fs"
+    "os"
+    "strings"
+)
+
+/*
+A gloss is a definition followed by optional example sentences, each in
+double quotes and separated by semicolons. e.g.
+
+    a member of the genus Canis; "the dog barked all night"
+
+Examples may be attributed ("..." - Shakespeare), may contain semicolons and
+quotes of their own, and a few definitions contain quoted words. An example
+starts with a quote at the beginning of the gloss or after a semicolon, and
+ends with the last quote before the next one.
+
+The Princeton WordNet Gloss Corpus (the glosstag directory of the WordNet
+distribution) has the same glosses tokenized, with many of their words tagged
+with the senses they are used in. LoadGlossTags reads its merged XML files.
+*/
+
+// Splits a gloss into its definition and its examples, without quotes.
+// The attribution of an example is kept after it. (e.g. "a waterless well -
+// John Doe")
+func parseGloss(gloss string) (string, []string) {
+    segments := []string{}
+    start := 0
+    for i := 0; i < len(gloss); i++ {
+        if gloss[i] != ';' {
+            continue
+        }
+        rest := strings.TrimLeft(gloss[i + 1:], " ")
+        if strings.HasPrefix(rest, "\"") {
+            segments = append(segments, gloss[start:i])
+            start = len(gloss) - len(rest)
+        }
+    }
+    segments = append(segments, gloss[start:])
+
+    definition := []string{}
+    examples := []string{}
+    for _, segment := range segments {
+        segment = strings.TrimSpace(segment)
+        if !strings.HasPrefix(segment, "\"") {
+            if segment != "" {
+                definition = append(definition, segment)
+            }
+            continue
+        }
+        example := segment[1:]
+        attribution := ""
+        if end := strings.LastIndex(example, "\""); end >= 0 {
+            example, attribution = example[:end], strings.TrimSpace(example[end + 1:])
+        }
+        if strings.HasPrefix(attribution, ";") {
+            // not an attribution, but more of the definition
+            definition = append(definition, strings.TrimSpace(attribution[1:]))
+            attribution = ""
+        }
+        example = strings.TrimSpace(example)
+        if attribution != "" {
+            example += " " + attribution
+        }
+        if example != "" {
+            examples = append(examples, example)
+        }
+    }
+    return strings.Join(definition, "; "), examples
+}
+
+type GlossToken struct {
+    Text string             // as it appears in the gloss
+    Lemma string            // lemma of the word or collocation, if known
+    Tag string              // "man" (manually tagged), "auto" (automatically tagged), "un" (untagged), "ignore", or "" for punctuation
+    SenseKeys []SenseKey    // the senses the word is tagged with
+}
+
+type TaggedGloss struct {
+    Definition []GlossToken
+    Examples [][]GlossToken
+}
+
+var GLOSS_TAG_FILENAMES = []string { "noun.xml", "verb.xml", "adj.xml", "adv.xml" }
+
+// Reads the disambiguated glosses of the Princeton WordNet Gloss Corpus
+// from a directory holding any of GLOSS_TAG_FILENAMES, such as
+// WordNet-3.0/glosstag/merged. They must match the WordNet version loaded.
+func (wn *WN) LoadGlossTags(glossTagDirname string) error {
+    return wn.LoadGlossTagsFS(os.DirFS(glossTagDirname))
+}
+
+// Like LoadGlossTags, but reads the files from fsys.
+func (wn *WN) LoadGlossTagsFS(fsys fs.FS) error {
+    glosses := map[synsetKey]*TaggedGloss{}
+    found := false
+    for _, filename := range GLOSS_TAG_FILENAMES {
+        if !dictFileExists(fsys, filename) {
+            continue
+        }
+        found = true
+        err := readGlossTagFile(fsys, filename, glosses)
+        if err != nil {
+            return err
+        }
+    }
+    if !found {
+        return fmt.Errorf("can't find any of %s", strings.Join(GLOSS_TAG_FILENAMES, ", "))
+    }
+    wn.taggedGlosses = glosses
+    return nil
+}
+
+// Returns the disambiguated gloss of a synset, or nil if there is none or
+// LoadGlossTags wasn't called.
+func (wn *WN) TaggedGloss(synset *Synset) *TaggedGloss {
+    if wn.taggedGlosses == nil && wn.snapshot != nil {
+        return wn.snapshot.taggedGloss(getSynsetKey(synset))
+    }
+    return wn.taggedGlosses[getSynsetKey(synset)]
+}
+
+// the parts of a gloss tag file this reads:
+// <synset ofs="02084071" pos="n">
+//   <gloss desc="wsd">
+//     <def> or <ex>, holding <wf>, <cf> and <punc> tokens in any nesting.
+//     Tagged tokens hold <id sk="..."/> elements. The words of a
+//     collocation are <cf coll="a"> tokens, tagged by a <glob coll="a">.
+func readGlossTagFile(fsys fs.FS, glossTagFilename string, glosses map[synsetKey]*TaggedGloss) error {
+    infile, err := openDictFile(fsys, glossTagFilename)
+    if err != nil {
+        return fmt.Errorf("can't open %s: %v", glossTagFilename, err)
+    }
+    defer infile.Close()
+
+    var synset synsetKey
+    var gloss *TaggedGloss = nil
+    var tokens []GlossToken = nil   // of the current <def> or <ex>
+    var token *GlossToken = nil     // the current <wf>, <cf>, <punc> or <glob>
+    tokenDepth := 0
+    var colls map[string][]int      // indices of the tokens of each collocation
+    var globs map[string]GlossToken // the lemma and senses of each collocation
+    globColl := ""
+
+    decoder := xml.NewDecoder(infile)
+    for {
+        t, err := decoder.Token()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return fmt.Errorf("can't parse %s: %v", glossTagFilename, err)
+        }
+        switch e := t.(type) {
+        case xml.StartElement:
+            if token != nil {
+                tokenDepth++
+            }
+            switch e.Name.Local {
+            case "synset":
+                var offset int
+                fmt.Sscanf(xmlAttr(e, "ofs"), "%d", &offset)
+                synset = synsetKey { normalizePos(oneCharPosTagToPosId(xmlAttr(e, "pos"))), offset }
+            case "gloss":
+                if xmlAttr(e, "desc") == "wsd" {
+                    gloss = &TaggedGloss { []GlossToken{}, [][]GlossToken{} }
+                    glosses[synset] = gloss
+                }
+            case "def", "ex":
+                if gloss != nil {
+                    tokens = []GlossToken{}
+                    colls = map[string][]int{}
+                    globs = map[string]GlossToken{}
+                }
+            case "wf", "cf", "punc", "glob":
+                if tokens == nil || token != nil {
+                    break
+                }
+                token = &GlossToken { Tag: xmlAttr(e, "tag") }
+                tokenDepth = 0
+                lemma := xmlAttr(e, "lemma")
+                if i := strings.IndexAny(lemma, "%|"); i >= 0 {
+                    lemma = lemma[:i]
+                }
+                token.Lemma = readStoredLemma(lemma)
+                coll := xmlAttr(e, "coll")
+                if e.Name.Local == "glob" {
+                    globColl = coll
+                } else if e.Name.Local == "cf" && coll != "" {
+                    for _, c := range strings.Split(coll, ",") {
+                        colls[c] = append(colls[c], len(tokens))
+                    }
+                }
+            case "id":
+                if token == nil {
+                    break
+                }
+                key, err := ParseSenseKey(xmlAttr(e, "sk"))
+                if err == nil {
+                    token.SenseKeys = append(token.SenseKeys, key)
+                    token.Lemma = key.Lemma
+                }
+            }
+        case xml.CharData:
+            if token != nil {
+                token.Text += string(e)
+            }
+        case xml.EndElement:
+            if token != nil && tokenDepth > 0 {
+                tokenDepth--
+                continue
+            }
+            switch e.Name.Local {
+            case "wf", "cf", "punc":
+                if token != nil {
+                    token.Text = strings.TrimSpace(token.Text)
+                    tokens = append(tokens, *token)
+                    token = nil
+                }
+            case "glob":
+                if token != nil {
+                    globs[globColl] = *token
+                    token = nil
+                }
+            case "def", "ex":
+                if tokens == nil {
+                    break
+                }
+                // the words of a collocation take its lemma and senses
+                for coll, indices := range colls {
+                    glob, exists := globs[coll]
+                    if !exists {
+                        continue
+                    }
+                    for _, i := range indices {
+                        tokens[i].Lemma = glob.Lemma
+                        tokens[i].Tag = glob.Tag
+                        tokens[i].SenseKeys = glob.SenseKeys
+                    }
+                }
+                if e.Name.Local == "def" {
+                    gloss.Definition = append(gloss.Definition, tokens...)
+                } else {
+                    gloss.Examples = append(gloss.Examples, tokens)
+                }
+                tokens = nil
+            case "gloss":
+                gloss = nil
+            }
+        }
+    }
+    return nil
+}
+
+func xmlAttr(e xml.StartElement, name string) string {
+    for _, attr := range e.Attr {
+        if attr.Name.Local == name {
+            return attr.Value
+        }
+    }
+    return ""
+}
@@ -0,0 +1,135 @@
+package gown
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestParseGloss(t *testing.T) {
+    tests := []struct {
+        gloss string
+        definition string
+        examples []string
+    } {
+        {
+            "a machine for performing calculations automatically",
+            "a machine for performing calculations automatically",
+            []string{},
+        },
+        {
+            "a tangible and visible entity; \"it was full of rackets, balls and other objects\"",
+            "a tangible and visible entity",
+            []string { "it was full of rackets, balls and other objects" },
+        },
+        {
+            // a semicolon in the definition
+            "any of various fissiped mammals; occurs in many breeds; \"the dog barked\"; \"the dog bit\"",
+            "any of various fissiped mammals; occurs in many breeds",
+            []string { "the dog barked", "the dog bit" },
+        },
+        {
+            // a semicolon and quotes in an example
+            "be exultant; \"he said \"no\"; then he left\"",
+            "be exultant",
+            []string { "he said \"no\"; then he left" },
+        },
+        {
+            // attributions
+            "lacking sufficient water; \"an arid climate\"; \"a waterless well\" - John Doe",
+            "lacking sufficient water",
+            []string { "an arid climate", "a waterless well - John Doe" },
+        },
+        {
+            // a quoted word in the definition
+            "the letter \"a\" used as a grade",
+            "the letter \"a\" used as a grade",
+            []string{},
+        },
+    }
+    for _, test := range tests {
+        definition, examples := parseGloss(test.gloss)
+        if definition != test.definition {
+            t.Errorf("expected the definition of %q to be %q, got %q", test.gloss, test.definition, definition)
+        }
+        if !reflect.DeepEqual(examples, test.examples) {
+            t.Errorf("expected the examples of %q to be %q, got %q", test.gloss, test.examples, examples)
+        }
+    }
+
+    wn := loadTestWordNet(t, LoadOptions{})
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+    if !strings.HasPrefix(dog.Definition, "a member of the genus Canis") {
+        t.Errorf("unexpected definition of dog %q", dog.Definition)
+    }
+    if !reflect.DeepEqual(dog.Examples, []string { "the dog barked all night" }) {
+        t.Errorf("unexpected examples of dog %q", dog.Examples)
+    }
+}
+
+func TestLoadGlossTags(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+
+    glossTagDir := t.TempDir()
+    if err := wn.LoadGlossTags(glossTagDir); err == nil {
+        t.Errorf("expected an error loading gloss tags from an empty directory")
+    }
+
+    xml := `<?xml version="1.0" encoding="utf-8"?>
+<wordnet>
+<synset id="n` + dog.ID().String()[:8] + `" ofs="` + dog.ID().String()[:8] + `" pos="n">
+  <gloss desc="orig">a member of the genus Canis; "the dog barked all night"</gloss>
+  <gloss desc="wsd">
+    <def id="d">
+      <wf id="w1" lemma="a%1" pos="DT" tag="ignore">a</wf>
+      <wf id="w2" lemma="member%1" pos="NN" tag="man"><id id="i1" lemma="member" sk="member%1:14:00::"/>member</wf>
+      <glob coll="a" id="g1" lemma="genus_canis%1" tag="man"><id coll="a" id="i2" lemma="genus canis" sk="genus_canis%1:05:00::"/>{genus Canis}</glob>
+      <cf coll="a" id="w3" lemma="genus%1" tag="un">genus</cf>
+      <cf coll="a" id="w4" lemma="canis%1" tag="un">Canis</cf>
+    </def>
+    <aux><punc>;</punc></aux>
+    <ex id="e"><qf><wf id="w5" lemma="the" tag="ignore">the</wf> <wf id="w6" lemma="dog%1" tag="auto"><id lemma="dog" sk="dog%1:05:00::"/>dog</wf> <wf id="w7" lemma="bark%2" tag="man"><id lemma="bark" sk="bark%2:32:00::"/>barked</wf></qf></ex>
+  </gloss>
+</synset>
+</wordnet>
+`
+    if err := os.WriteFile(filepath.Join(glossTagDir, "noun.xml"), []byte(xml), 0644); err != nil {
+        t.Fatal(err)
+    }
+    if err := wn.LoadGlossTags(glossTagDir); err != nil {
+        t.Fatalf("can't load gloss tags: %v", err)
+    }
+
+    gloss := wn.TaggedGloss(dog)
+    if gloss == nil {
+        t.Fatalf("expected a tagged gloss for dog")
+    }
+    texts := []string{}
+    for _, token := range gloss.Definition {
+        texts = append(texts, token.Text)
+    }
+    if !reflect.DeepEqual(texts, []string { "a", "member", "genus", "Canis" }) {
+        t.Errorf("unexpected definition tokens %q", texts)
+    }
+    if gloss.Definition[1].Lemma != "member" || len(gloss.Definition[1].SenseKeys) != 1 ||
+        gloss.Definition[1].SenseKeys[0].String() != "member%1:14:00::" {
+        t.Errorf("unexpected tagging of \"member\": %v", gloss.Definition[1])
+    }
+    // the words of a collocation are tagged with it
+    for _, token := range gloss.Definition[2:] {
+        if token.Lemma != "genus canis" || token.Tag != "man" || len(token.SenseKeys) != 1 {
+            t.Errorf("unexpected tagging of %q: %v", token.Text, token)
+        }
+    }
+    if len(gloss.Examples) != 1 || len(gloss.Examples[0]) != 3 || gloss.Examples[0][2].Lemma != "bark" {
+        t.Errorf("unexpected examples %v", gloss.Examples)
+    }
+
+    cat := wn.LookupWithPartOfSpeechAndSense("cat", POS_NOUN, 1).GetSynsetPtr()
+    if wn.TaggedGloss(cat) != nil {
+        t.Errorf("expected no tagged gloss for cat")
+    }
+}
@@ -2,15 +2,51 @@ package gown
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"strings"
+	"sync"
 )
 
 type WN struct {
-	senseIndex  senseIndex
-	PosIndicies map[int]*dataIndex
-	posData     map[int]*dataFile
-	exceptions  []map[string]string
+	// guards senseIndex and posData, which are replaced rather than changed
+	// when relationships are added
+	dataLock             sync.RWMutex
+	senseIndex           senseIndex
+	PosIndicies          map[int]*dataIndex
+	posData              map[int]*dataFile
+	lemmas               []DataIndexPair // of every part of speech, sorted
+	exceptions           []map[string][]string
+	inflectionExceptions []map[string][]string
+	verbFrameStrings     []string
+	verbSentences        map[int]string
+	verbSentenceIndex    map[string][]int
+
+	lazy     *lazyData
+	snapshot *snapshotReader // set if loaded from a snapshot
+
+	taxonomyDepthsLock sync.Mutex
+	taxonomyDepths     map[int]int
+
+	verbGroupsLock sync.Mutex
+	verbGroups     map[int][]int
+
+	hypernymIndexLock sync.RWMutex
+	hypernymIndex     *hypernymIndex
+
+	searchIndexLock sync.RWMutex
+	searchIndex     *searchIndex
+
+	fuzzyIndexLock sync.RWMutex
+	fuzzyIndex     *fuzzyIndex
+
+	collocationIndexLock sync.RWMutex
+	collocationIndex     *collocationNode
+
+	taggedGlosses map[synsetKey]*TaggedGloss
+
+	iliToSynset map[string]SynsetID
+	synsetToIli map[synsetKey]string
 }
 
 func GetWordNetDictDir() (string, error) {
@@ -52,7 +88,24 @@ func GetWordNetDictDir() (string, error) {
 	return "", fmt.Errorf("Can't find WordNet dictionary")
 }
 
+// Loads the WordNet database from a dictionary directory. (see
+// GetWordNetDictDir)
 func LoadWordNet(dictDirname string) (*WN, error) {
+	return LoadWordNetFS(os.DirFS(dictDirname))
+}
+
+// Loads the WordNet database from the root of fsys. This serves dictionaries
+// embedded with go:embed (use fs.Sub to get to the dict directory), read
+// from an archive, or stored on disk. Any file may be gzip compressed with
+// a ".gz" suffix.
+func LoadWordNetFS(fsys fs.FS) (*WN, error) {
+	return LoadWordNetWithOptions(fsys, LoadOptions{})
+}
+
+// Loads the WordNet database from the root of fsys. With opts.Lazy set,
+// only the verb frames are read up front and everything else is parsed on
+// demand; call Close when done with it.
+func LoadWordNetWithOptions(fsys fs.FS, opts LoadOptions) (*WN, error) {
 	wn := &WN{
 		senseIndex:  nil,
 		PosIndicies: map[int]*dataIndex{},
@@ -60,27 +113,84 @@ func LoadWordNet(dictDirname string) (*WN, error) {
 	}
 
 	var err error = nil
+	if opts.Lazy {
+		wn.lazy, err = loadLazyData(fsys, opts.SynsetCacheSize)
+		if err != nil {
+			return nil, err
+		}
+		err = wn.loadVerbFrames(fsys)
+		if err != nil {
+			wn.Close()
+			return nil, err
+		}
+		wn.applyLoadOptions(opts)
+		return wn, nil
+	}
+
 	pos_file_names := []string{"", "noun", "verb", "adj", "adv"}
 	for i := 1; i < len(pos_file_names); i++ {
-		wn.PosIndicies[i], err = readPosIndex(dictDirname + "/index." + pos_file_names[i])
+		wn.PosIndicies[i], err = readPosIndex(fsys, "index."+pos_file_names[i])
 		if err != nil {
 			return nil, err
 		}
-		wn.posData[i], err = readPosData(dictDirname + "/data." + pos_file_names[i])
+		wn.posData[i], err = readPosData(fsys, "data."+pos_file_names[i])
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	wn.senseIndex, err = loadSenseIndex(wn, dictDirname + "/index.sense")
+	wn.buildLemmaList()
+
+	wn.senseIndex, err = loadSenseIndex(wn, fsys, "index.sense")
+	if err != nil {
+		return nil, err
+	}
+
+	err = wn.loadVerbFrames(fsys)
 	if err != nil {
 		return nil, err
 	}
 
+	wn.applyLoadOptions(opts)
 	return wn, nil
 }
 
+// runs the post-load passes requested by opts
+func (wn *WN) applyLoadOptions(opts LoadOptions) {
+	if opts.ConnectVerbGroups {
+		wn.ConnectVerbGroups()
+	}
+	if opts.InverseRelationships {
+		wn.AddInverseRelationships()
+	}
+	if opts.HypernymIndex {
+		wn.BuildHypernymIndex()
+	}
+}
+
+// Releases the files held open by a lazily loaded WN or a snapshot. It does
+// nothing for a fully loaded one.
+func (wn *WN) Close() error {
+	if wn.lazy == nil {
+		return nil
+	}
+	return wn.lazy.Close()
+}
+
+// the sense index entries of a lemma
+func (wn *WN) senses(lemma string) []SenseIndexEntry {
+	if wn.lazy != nil {
+		return wn.lazy.senses(wn, lemma)
+	}
+	wn.dataLock.RLock()
+	defer wn.dataLock.RUnlock()
+	return wn.senseIndex[lemma]
+}
+
 func (wn *WN) LookupWithPartOfSpeech(lemma string, pos int) *DataIndexEntry {
+	if wn.lazy != nil {
+		return wn.lazy.lookupIndex(pos, lemma)
+	}
 	posIndexPtr, exists := wn.PosIndicies[pos]
 	if !exists {
 		return nil
@@ -94,7 +204,7 @@ func (wn *WN) LookupWithPartOfSpeech(lemma string, pos int) *DataIndexEntry {
 }
 
 func (wn *WN) LookupSensesWithPartOfSpeech(lemma string, pos int) []*SenseIndexEntry {
-	senses, _ := wn.senseIndex[lemma]
+	senses := wn.senses(lemma)
 	ret := make([]*SenseIndexEntry, 0, len(senses))
 	for i, _ := range senses {
 		if senses[i].PartOfSpeech == pos {
@@ -105,7 +215,7 @@ func (wn *WN) LookupSensesWithPartOfSpeech(lemma string, pos int) []*SenseIndexE
 }
 
 func (wn *WN) LookupWithPartOfSpeechAndSense(lemma string, pos int, senseId int) *SenseIndexEntry {
-	senses, _ := wn.senseIndex[lemma]
+	senses := wn.senses(lemma)
 	for _, sense := range senses {
 		if (sense.PartOfSpeech == pos) && (sense.SenseNumber == senseId) {
 			return &sense
@@ -115,10 +225,7 @@ func (wn *WN) LookupWithPartOfSpeechAndSense(lemma string, pos int, senseId int)
 }
 
 func (wn *WN) Lookup(lemma string) []*SenseIndexEntry {
-	senseEntries, exists := wn.senseIndex[strings.ToLower(lemma)]
-	if !exists {
-		return []*SenseIndexEntry{}
-	}
+	senseEntries := wn.senses(strings.ToLower(lemma))
 	ret := make([]*SenseIndexEntry, len(senseEntries))
 	for i, _ := range senseEntries {
 		ret[i] = &senseEntries[i]
@@ -126,11 +233,29 @@ func (wn *WN) Lookup(lemma string) []*SenseIndexEntry {
 	return ret
 }
 
+// Like Lookup, but only returns the senses written exactly as the lemma is in
+// their synsets, so "Mars" finds the planet but "mars" doesn't.
+func (wn *WN) LookupCaseSensitive(lemma string) []*SenseIndexEntry {
+	senseEntries := wn.senses(strings.ToLower(lemma))
+	ret := []*SenseIndexEntry{}
+	for i, _ := range senseEntries {
+		if senseEntries[i].Orthography == lemma {
+			ret = append(ret, &senseEntries[i])
+		}
+	}
+	return ret
+}
+
 func (wn *WN) GetSynset(pos int, synsetOffset int) *Synset {
 	if pos == POS_ADJECTIVE_SATELLITE {
 		pos = POS_ADJECTIVE
 	}
+	if wn.lazy != nil {
+		return wn.lazy.getSynset(pos, synsetOffset)
+	}
+	wn.dataLock.RLock()
 	idxPtr, exists := wn.posData[pos]
+	wn.dataLock.RUnlock()
 	if !exists || idxPtr == nil {
 		return nil
 	}
@@ -142,6 +267,9 @@ func (wn *WN) GetSynset(pos int, synsetOffset int) *Synset {
 }
 
 func (wn *WN) TraverseDataIndex(pos int) <-chan DataIndexPair {
+	if wn.lazy != nil {
+		return wn.lazy.traverseDataIndex(pos)
+	}
 	table, ok := wn.PosIndicies[pos]
 	if !ok {
 		return nil
@@ -157,9 +285,15 @@ func (wn *WN) TraverseDataIndex(pos int) <-chan DataIndexPair {
 }
 
 func (wn *WN) Iter() <-chan *Synset {
+	if wn.lazy != nil {
+		return wn.lazy.iter()
+	}
+	wn.dataLock.RLock()
+	posData := wn.posData
+	wn.dataLock.RUnlock()
 	outChan := make(chan *Synset)
 	go func() {
-		for _, datFile := range wn.posData {
+		for _, datFile := range posData {
 			for _, synset := range *datFile {
 				words := make([]string, len(synset.Words))
 				for i, w := range synset.Words {
@@ -169,18 +303,29 @@ func (wn *WN) Iter() <-chan *Synset {
 				for i, w := range synset.LexIds {
 					lexids[i] = w
 				}
+				markers := make([]int, len(synset.Markers))
+				copy(markers, synset.Markers)
 				edges := make([]RelationshipEdge, len(synset.Relationships))
 				for i, w := range synset.Relationships {
 					edges[i] = w
 				}
+				var frames []VerbFrame = nil
+				if synset.Frames != nil {
+					frames = make([]VerbFrame, len(synset.Frames))
+					copy(frames, synset.Frames)
+				}
 				outChan <- &Synset{
 					SynsetOffset:       synset.SynsetOffset,
 					LexographerFilenum: synset.LexographerFilenum,
 					PartOfSpeech:       synset.PartOfSpeech,
 					Words:              words,
 					LexIds:             lexids,
+					Markers:            markers,
 					Relationships:      edges,
+					Frames:             frames,
 					Gloss:              synset.Gloss,
+					Definition:         synset.Definition,
+					Examples:           append([]string{}, synset.Examples...),
 				}
 			}
 		}
@@ -190,9 +335,15 @@ func (wn *WN) Iter() <-chan *Synset {
 }
 
 func (wn *WN) IterSenses() <-chan *SenseIndexEntry {
+	if wn.lazy != nil {
+		return wn.lazy.iterSenses(wn)
+	}
+	wn.dataLock.RLock()
+	index := wn.senseIndex
+	wn.dataLock.RUnlock()
 	outchan := make(chan *SenseIndexEntry)
 	go func() {
-		for _, senses := range wn.senseIndex {
+		for _, senses := range index {
 			for i, _ := range senses {
 				outchan <- &senses[i]
 			}
@@ -1,9 +1,59 @@
 package gown
 
 import (
+    "os"
+    "reflect"
     "testing"
 )
 
+// A small dictionary in the WordNet format holding the synsets the tests
+// look up, so they run without WordNet installed.
+const TEST_DICT_DIR = "testdata/dict"
+
+// Loads the test dictionary with opts, and its morphology data.
+func loadTestWordNet(t *testing.T, opts LoadOptions) *WN {
+    t.Helper()
+    wn, err := LoadWordNetWithOptions(os.DirFS(TEST_DICT_DIR), opts)
+    if err != nil {
+        t.Fatalf("can't load WordNet from %s: %v", TEST_DICT_DIR, err)
+    }
+    t.Cleanup(func() { wn.Close() })
+    err = wn.InitMorphDataFS(os.DirFS(TEST_DICT_DIR))
+    if err != nil {
+        t.Fatalf("failed to load morph data: %v", err)
+    }
+    return wn
+}
+
+// Runs test with the test dictionary loaded eagerly, then lazily.
+func forEachLoadMode(t *testing.T, opts LoadOptions, test func(t *testing.T, wn *WN)) {
+    for _, lazy := range []bool { false, true } {
+        opts.Lazy = lazy
+        name := "eager"
+        if lazy {
+            name = "lazy"
+        }
+        t.Run(name, func(t *testing.T) {
+            test(t, loadTestWordNet(t, opts))
+        })
+    }
+}
+
+// Loads the installed WordNet, for the benchmarks and for the tests comparing
+// with reference values computed on WordNet 3.0. Skips if it isn't installed.
+func loadSystemWordNet(t testing.TB) (*WN, string) {
+    t.Helper()
+    dictDir, err := GetWordNetDictDir()
+    if err != nil {
+        t.Skipf("WordNet isn't installed: %v", err)
+    }
+    wn, err := LoadWordNet(dictDir)
+    if err != nil {
+        t.Fatalf("can't load WordNet from %s: %v", dictDir, err)
+    }
+    return wn, dictDir
+}
+
 func BenchmarkLoadWordNet(b *testing.B) {
     dictDir, _ := GetWordNetDictDir()
     b.ResetTimer()
@@ -30,10 +80,34 @@ func BenchmarkLookup(b *testing.B) {
     }
 }
 
+func BenchmarkIsA(b *testing.B) {
+    wn, _ := loadSystemWordNet(b)
+    wn.BuildHypernymIndex()
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+    entity := wn.LookupWithPartOfSpeechAndSense("entity", POS_NOUN, 1).GetSynsetPtr()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        wn.IsA(dog, entity)
+    }
+}
+
+func BenchmarkIsAWithoutIndex(b *testing.B) {
+    wn, _ := loadSystemWordNet(b)
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+    entity := wn.LookupWithPartOfSpeechAndSense("entity", POS_NOUN, 1).GetSynsetPtr()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        wn.IsA(dog, entity)
+    }
+}
+
 func TestMorph(t *testing.T) {
     dictDir, _ := GetWordNetDictDir()
     wn, _ := LoadWordNet(dictDir)
-    wn.InitMorphData(dictDir)
+    err := wn.InitMorphData(dictDir)
+    if err != nil {
+        t.Fatalf("failed to load morph data: %v", err)
+    }
     poses := []int {
         POS_VERB, // are
         POS_NOUN, // splits
@@ -117,6 +191,50 @@ func TestMorph(t *testing.T) {
     }
 }
 
+func TestMorphAll(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    tests := []struct {
+        word string
+        pos int
+        expected []string
+    } {
+        { "axes", POS_NOUN, []string { "ax", "axis", "axe" } },
+        { "attorneys general", POS_NOUN, []string { "attorney general" } },
+        { "catsful", POS_NOUN, []string { "catful" } },
+        { "dogs", POS_NOUN, []string { "dog" } },
+        { "dog", POS_NOUN, []string { "dog" } },
+        { "looking for", POS_VERB, []string { "look for" } },
+        { "gave up", POS_VERB, []string { "give up" } },
+        { "swimming", POS_VERB, []string { "swim" } },
+        { "better", POS_ADVERB, []string { "well" } },
+        { "ewoks", POS_NOUN, []string {} },
+    }
+
+    for _, test := range tests {
+        actual := wn.MorphAll(test.word, test.pos)
+        if !reflect.DeepEqual(actual, test.expected) {
+            t.Errorf("for %s/%d expected %v but got %v\n", test.word, test.pos, test.expected, actual)
+        }
+    }
+}
+
+func TestMorphAnyPartOfSpeech(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+
+    results := wn.MorphAnyPartOfSpeech("saw")
+    if len(results) == 0 || results[0].Lemma != "see" || results[0].PartOfSpeech != POS_VERB {
+        t.Fatalf("expected see/%d first but got %v", POS_VERB, results)
+    }
+    for i := 1; i < len(results); i++ {
+        if results[i].TagSenseCount > results[i - 1].TagSenseCount {
+            t.Errorf("results not ordered by TagSenseCount: %v", results)
+        }
+    }
+    if len(wn.MorphAnyPartOfSpeech("ewoks")) != 0 {
+        t.Errorf("expected no results for ewoks")
+    }
+}
+
 func TestIterate(t *testing.T) {
     dictDir, _ := GetWordNetDictDir()
     wn, _ := LoadWordNet(dictDir)
@@ -138,3 +256,46 @@ func TestIterate(t *testing.T) {
         t.Errorf("expected to read at least %v, but only got %v", atLeast, i)
     }
 }
+
+func TestIterateKeepsMarkers(t *testing.T) {
+    forEachLoadMode(t, LoadOptions{}, func(t *testing.T, wn *WN) {
+        found := false
+        for synset := range wn.Iter() {
+            for i, word := range synset.Words {
+                if word != "galore" {
+                    continue
+                }
+                found = true
+                if len(synset.Markers) != len(synset.Words) || synset.Markers[i] != SYNTACTIC_MARKER_IMMEDIATELY_POSTNOMIAL_POSITION {
+                    t.Errorf("expected \"galore\" to be immediately postnominal, got markers %v", synset.Markers)
+                }
+            }
+        }
+        if !found {
+            t.Errorf("expected to iterate over \"galore\"")
+        }
+    })
+}
+
+func TestLookupCaseSensitive(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+
+    for _, sense := range wn.Lookup("mars") {
+        if sense.Orthography != "Mars" {
+            t.Errorf("expected %s to be written \"Mars\", got %q", sense.SenseKey, sense.Orthography)
+        }
+    }
+    mars := wn.LookupCaseSensitive("Mars")
+    if len(mars) == 0 || len(mars) != len(wn.Lookup("mars")) {
+        t.Errorf("expected every sense of \"mars\" to be found as \"Mars\", got %d", len(mars))
+    }
+    if len(wn.LookupCaseSensitive("mars")) != 0 {
+        t.Errorf("expected no senses written \"mars\"")
+    }
+
+    // common nouns are lower case
+    dogs := wn.LookupCaseSensitive("dog")
+    if len(dogs) == 0 || dogs[0].Orthography != "dog" {
+        t.Errorf("expected to find \"dog\"")
+    }
+}
@@ -0,0 +1,240 @@
+package gown
+
+import (
+    "sort"
+)
+
+/*
+The hypernym index labels the taxonomy, along HYPERNYM_RELATIONSHIP and
+INSTANCE_HYPERNYM_RELATIONSHIP edges, with intervals of a post-order
+numbering. Synsets on a hypernym cycle are all ancestors of each other, so
+the strongly connected components of the graph are found first and each is
+labeled as one node of the acyclic graph they form. A depth first walk down
+a spanning tree of that graph numbers every component after its
+descendants in the tree, so the descendants of a component in the tree are
+exactly the numbers from its first descendant's up to its own. The
+descendants it only reaches through other parents are added as more
+intervals, which are merged wherever they overlap or touch.
+
+A subsumption check is then two map lookups and a search of the intervals of
+the ancestor. WordNet has little multiple inheritance, and almost every
+synset has a single interval, so the check takes constant time.
+*/
+
+type hypernymIndex struct {
+    ids map[synsetKey]int32
+    components []int32          // indexed by id
+    numbers []int32             // post-order number of each component
+    intervals [][]hypernymInterval // of the descendants of each component, sorted
+}
+
+type hypernymInterval struct {
+    first int32
+    last int32
+}
+
+// Builds the index used by IsA. It's also built by the HypernymIndex load
+// option, and dropped by AddInverseRelationships and ConnectVerbGroups, so
+// build it after them.
+func (wn *WN) BuildHypernymIndex() {
+    index := &hypernymIndex {
+        ids: map[synsetKey]int32{},
+    }
+    keys := []synsetKey{}
+    hypernymKeys := [][]synsetKey{}
+    for synset := range wn.Iter() {
+        index.ids[getSynsetKey(synset)] = int32(len(keys))
+        keys = append(keys, getSynsetKey(synset))
+        synsetHypernyms := []synsetKey{}
+        for _, edge := range synset.Relationships {
+            if containsInt(HYPERNYM_RELATIONSHIPS, edge.RelationshipType) {
+                synsetHypernyms = append(synsetHypernyms, synsetKey { normalizePos(edge.PartOfSpeech), edge.SynsetOffset })
+            }
+        }
+        hypernymKeys = append(hypernymKeys, synsetHypernyms)
+    }
+    hypernyms := make([][]int32, len(keys))
+    for id, synsetHypernyms := range hypernymKeys {
+        for _, hypernym := range synsetHypernyms {
+            if hypernymId, exists := index.ids[hypernym]; exists {
+                hypernyms[id] = append(hypernyms[id], hypernymId)
+            }
+        }
+    }
+
+    // Tarjan's algorithm. It completes a component only after every
+    // component it reaches, so components are numbered from the top of the
+    // taxonomy down.
+    index.components = make([]int32, len(keys))
+    order := make([]int32, len(keys))     // of visiting each synset, from 1
+    lowlinks := make([]int32, len(keys))
+    onStack := make([]bool, len(keys))
+    stack := []int32{}
+    visited := int32(0)
+    componentCount := int32(0)
+    var connect func(id int32)
+    connect = func(id int32) {
+        visited++
+        order[id] = visited
+        lowlinks[id] = visited
+        stack = append(stack, id)
+        onStack[id] = true
+        for _, hypernym := range hypernyms[id] {
+            if order[hypernym] == 0 {
+                connect(hypernym)
+                lowlinks[id] = minInt32(lowlinks[id], lowlinks[hypernym])
+            } else if onStack[hypernym] {
+                lowlinks[id] = minInt32(lowlinks[id], order[hypernym])
+            }
+        }
+        if lowlinks[id] != order[id] {
+            return
+        }
+        for {
+            member := stack[len(stack) - 1]
+            stack = stack[:len(stack) - 1]
+            onStack[member] = false
+            index.components[member] = componentCount
+            if member == id {
+                break
+            }
+        }
+        componentCount++
+    }
+    for id, _ := range keys {
+        if order[id] == 0 {
+            connect(int32(id))
+        }
+    }
+
+    // the acyclic graph of the components, from hypernyms to hyponyms
+    hyponyms := make([][]int32, componentCount)
+    hasHypernym := make([]bool, componentCount)
+    seen := map[[2]int32]bool{}
+    for id, synsetHypernyms := range hypernyms {
+        component := index.components[id]
+        for _, hypernym := range synsetHypernyms {
+            hypernymComponent := index.components[hypernym]
+            edge := [2]int32 { hypernymComponent, component }
+            if hypernymComponent == component || seen[edge] {
+                continue
+            }
+            seen[edge] = true
+            hyponyms[hypernymComponent] = append(hyponyms[hypernymComponent], component)
+            hasHypernym[component] = true
+        }
+    }
+
+    // number the components in post-order down a spanning tree
+    index.numbers = make([]int32, componentCount)
+    index.intervals = make([][]hypernymInterval, componentCount)
+    numbered := make([]bool, componentCount)
+    next := int32(0)
+    var number func(component int32)
+    number = func(component int32) {
+        numbered[component] = true
+        first := next
+        for _, hyponym := range hyponyms[component] {
+            if !numbered[hyponym] {
+                number(hyponym)
+            }
+        }
+        index.numbers[component] = next
+        index.intervals[component] = []hypernymInterval { { first, next } }
+        next++
+    }
+    for component := int32(0); component < componentCount; component++ {
+        if !hasHypernym[component] {
+            number(component)
+        }
+    }
+
+    // add the intervals of the hyponyms, which were completed later
+    for component := componentCount - 1; component >= 0; component-- {
+        intervals := index.intervals[component]
+        for _, hyponym := range hyponyms[component] {
+            intervals = append(intervals, index.intervals[hyponym]...)
+        }
+        index.intervals[component] = mergeHypernymIntervals(intervals)
+    }
+
+    wn.hypernymIndexLock.Lock()
+    wn.hypernymIndex = index
+    wn.hypernymIndexLock.Unlock()
+}
+
+func mergeHypernymIntervals(intervals []hypernymInterval) []hypernymInterval {
+    if len(intervals) < 2 {
+        return intervals
+    }
+    sort.Slice(intervals, func(i, j int) bool { return intervals[i].first < intervals[j].first })
+    merged := []hypernymInterval { intervals[0] }
+    for _, interval := range intervals[1:] {
+        last := &merged[len(merged) - 1]
+        if interval.first <= last.last + 1 {
+            if interval.last > last.last {
+                last.last = interval.last
+            }
+        } else {
+            merged = append(merged, interval)
+        }
+    }
+    return merged
+}
+
+func minInt32(a int32, b int32) int32 {
+    if a < b {
+        return a
+    }
+    return b
+}
+
+// Drops the index, which is out of date once relationships are added.
+func (wn *WN) dropHypernymIndex() {
+    wn.hypernymIndexLock.Lock()
+    wn.hypernymIndex = nil
+    wn.hypernymIndexLock.Unlock()
+}
+
+// Returns true if ancestor is synset or one of its (instance) hypernyms,
+// directly or indirectly. (e.g. dog is a carnivore) This takes constant
+// time after BuildHypernymIndex, and walks the hypernyms otherwise.
+func (wn *WN) IsA(synset *Synset, ancestor *Synset) bool {
+    if synset == nil || ancestor == nil {
+        return false
+    }
+    k := getSynsetKey(synset)
+    ancestorKey := getSynsetKey(ancestor)
+    if k == ancestorKey {
+        return true
+    }
+
+    wn.hypernymIndexLock.RLock()
+    index := wn.hypernymIndex
+    wn.hypernymIndexLock.RUnlock()
+    if index != nil {
+        id, exists := index.ids[k]
+        ancestorId, ancestorExists := index.ids[ancestorKey]
+        if !exists || !ancestorExists {
+            return false
+        }
+        component := index.components[id]
+        ancestorComponent := index.components[ancestorId]
+        if component == ancestorComponent {
+            return true
+        }
+        n := index.numbers[component]
+        intervals := index.intervals[ancestorComponent]
+        i := sort.Search(len(intervals), func(i int) bool { return intervals[i].last >= n })
+        return i < len(intervals) && intervals[i].first <= n
+    }
+
+    found := false
+    wn.Walk(synset, HYPERNYM_RELATIONSHIPS, -1, func(hypernym *Synset, depth int) bool {
+        if getSynsetKey(hypernym) == ancestorKey {
+            found = true
+        }
+        return !found
+    })
+    return found
+}
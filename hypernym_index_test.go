@@ -0,0 +1,93 @@
+package gown
+
+import (
+    "testing"
+)
+
+func TestIsA(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions { HypernymIndex: true })
+    unindexedWn := loadTestWordNet(t, LoadOptions{})
+
+    synset := func(lemma string) *Synset {
+        return wn.LookupWithPartOfSpeechAndSense(lemma, POS_NOUN, 1).GetSynsetPtr()
+    }
+    tests := []struct {
+        synset string
+        ancestor string
+        expected bool
+    } {
+        { "dog", "dog", true },
+        { "dog", "carnivore", true },
+        { "dog", "entity", true },
+        { "mars", "entity", true }, // through an instance hypernym
+        { "dog", "cat", false },
+        { "carnivore", "dog", false },
+    }
+    for _, test := range tests {
+        for _, w := range []*WN { wn, unindexedWn } {
+            if w.IsA(synset(test.synset), synset(test.ancestor)) != test.expected {
+                t.Errorf("expected IsA(%s, %s) to be %v", test.synset, test.ancestor, test.expected)
+            }
+        }
+    }
+
+    // the index agrees with walking the hypernyms
+    checkIsA(t, wn, unindexedWn)
+}
+
+// Checks that the index of wn agrees with walking the hypernyms of every
+// pair of synsets in unindexedWn.
+func checkIsA(t *testing.T, wn *WN, unindexedWn *WN) {
+    synsets := []*Synset{}
+    for s := range wn.Iter() {
+        synsets = append(synsets, s)
+    }
+    for _, s := range synsets {
+        for _, ancestor := range synsets {
+            if wn.IsA(s, ancestor) != unindexedWn.IsA(s, ancestor) {
+                t.Fatalf("expected IsA(%v, %v) to be %v", s.Words, ancestor.Words, unindexedWn.IsA(s, ancestor))
+            }
+        }
+    }
+}
+
+func TestIsAWithCycles(t *testing.T) {
+    forEachLoadMode(t, LoadOptions { HypernymIndex: true }, func(t *testing.T, wn *WN) {
+        unindexedWn := loadTestWordNet(t, LoadOptions { Lazy: wn.lazy != nil })
+        dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+        carnivore := wn.LookupWithPartOfSpeechAndSense("carnivore", POS_NOUN, 1).GetSynsetPtr()
+        entity := wn.LookupWithPartOfSpeechAndSense("entity", POS_NOUN, 1).GetSynsetPtr()
+
+        // make carnivore a dog, closing a cycle through canine
+        cycle := map[synsetKey][]RelationshipEdge {
+            getSynsetKey(carnivore): { { HYPERNYM_RELATIONSHIP, dog.SynsetOffset, POS_NOUN, 0, 0 } },
+        }
+        wn.addRelationships(cycle)
+        unindexedWn.addRelationships(cycle)
+        if wn.hypernymIndex != nil {
+            t.Fatalf("expected adding relationships to drop the index")
+        }
+        wn.BuildHypernymIndex()
+
+        if !wn.IsA(carnivore, dog) || !wn.IsA(dog, carnivore) || !wn.IsA(carnivore, entity) {
+            t.Errorf("expected every synset of the cycle to be an ancestor of the others")
+        }
+        if wn.IsA(entity, dog) {
+            t.Errorf("expected entity not to be a dog")
+        }
+        checkIsA(t, wn, unindexedWn)
+    })
+}
+
+func TestHypernymIndexDropped(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions { HypernymIndex: true })
+    wn.AddInverseRelationships()
+    if wn.hypernymIndex != nil {
+        t.Errorf("expected AddInverseRelationships to drop the index")
+    }
+    wn.BuildHypernymIndex()
+    wn.ConnectVerbGroups()
+    if wn.hypernymIndex != nil {
+        t.Errorf("expected ConnectVerbGroups to drop the index")
+    }
+}
@@ -0,0 +1,307 @@
+package gown
+
+import (
+    "sort"
+    "strings"
+    "unicode/utf8"
+)
+
+/*
+Inflection is the inverse of Morph: it generates the surface forms of a lemma.
+Irregular forms come from the morphology exception lists read backwards (e.g.
+"swam swim" in verb.exc gives "swam" as a past form of "swim"), so
+InitMorphData should be called first. The exception lists also contain the
+forms that double their final consonant (e.g. "stopped stop"). Regular forms
+are generated from the suffix rules, except where an irregular form of the
+same kind exists.
+*/
+
+const INFLECTION_PLURAL int = 1
+const INFLECTION_THIRD_PERSON_SINGULAR int = 2
+const INFLECTION_PRESENT int = 3            // other present tense forms (e.g. "are")
+const INFLECTION_PAST int = 4               // past tense or past participle
+const INFLECTION_PRESENT_PARTICIPLE int = 5 // also the gerund
+const INFLECTION_COMPARATIVE int = 6
+const INFLECTION_SUPERLATIVE int = 7
+
+var INFLECTION_ID_TO_STRING = map[int]string {
+    INFLECTION_PLURAL: "plural",
+    INFLECTION_THIRD_PERSON_SINGULAR: "third person singular",
+    INFLECTION_PRESENT: "present",
+    INFLECTION_PAST: "past",
+    INFLECTION_PRESENT_PARTICIPLE: "present participle",
+    INFLECTION_COMPARATIVE: "comparative",
+    INFLECTION_SUPERLATIVE: "superlative",
+}
+
+type Inflection struct {
+    Form string
+    Kind int           // INFLECTION_PLURAL, etc.
+}
+
+// the inflections of each part of speech, in the order they're returned
+var inflectionKinds = [][]int {
+    []int { INFLECTION_PLURAL }, // noun
+    []int { INFLECTION_THIRD_PERSON_SINGULAR, INFLECTION_PRESENT, INFLECTION_PAST, INFLECTION_PRESENT_PARTICIPLE }, // verb
+    []int { INFLECTION_COMPARATIVE, INFLECTION_SUPERLATIVE }, // adjective
+    []int { INFLECTION_COMPARATIVE, INFLECTION_SUPERLATIVE }, // adverb
+}
+
+// Sets the morphology exception lists, and indexes them by base form for
+// Inflect.
+func (wn *WN) setExceptions(exceptions []map[string][]string) {
+    wn.exceptions = exceptions
+    wn.inflectionExceptions = make([]map[string][]string, len(exceptions))
+    for posIndex, posExceptions := range exceptions {
+        reversed := map[string][]string{}
+        for inflectedForm, baseForms := range posExceptions {
+            for _, baseForm := range baseForms {
+                reversed[baseForm] = appendUnique(reversed[baseForm], inflectedForm)
+            }
+        }
+        // the map iteration order is random
+        for _, inflectedForms := range reversed {
+            sort.Strings(inflectedForms)
+        }
+        wn.inflectionExceptions[posIndex] = reversed
+    }
+}
+
+// Returns the inflected forms of a lemma. (e.g. "swim" -> swims, swam, swum,
+// swimming) The lemma itself is not included. Nouns have plurals, verbs
+// third person singular, past and present participle forms and adjectives
+// and adverbs comparatives and superlatives. Adverbs only have irregular
+// forms. Multi-word nouns without an irregular form are inflected on the
+// last word and verbs on the first one (e.g. "give up" -> "gave up"). The
+// forms keep the case of the lemma. (e.g. "Mars" -> "Marses")
+func (wn *WN) Inflect(lemma string, partOfSpeech int) []Inflection {
+    ret := []Inflection{}
+    partOfSpeechIndex := getPosIndex(partOfSpeech)
+    if partOfSpeechIndex < 0 || partOfSpeechIndex >= len(inflectionKinds) {
+        return ret
+    }
+    original := strings.Join(strings.Fields(strings.Replace(lemma, "_", " ", -1)), " ")
+    // the exception lists and the rules are in lower case
+    lemma = strings.ToLower(original)
+    if lemma == "" {
+        return ret
+    }
+
+    irregulars := map[int][]string{}
+    if wn.inflectionExceptions != nil {
+        for _, form := range wn.inflectionExceptions[partOfSpeechIndex][lemma] {
+            kind := irregularInflectionKind(form, lemma, partOfSpeech)
+            irregulars[kind] = append(irregulars[kind], form)
+        }
+    }
+
+    if len(irregulars) == 0 && strings.Contains(lemma, " ") &&
+        (partOfSpeech == POS_NOUN || partOfSpeech == POS_VERB) {
+        // inflect the last word of a noun, or the first word of a verb
+        // (e.g. "give up" -> "gave up")
+        words := strings.Split(original, " ")
+        headIndex := len(words) - 1
+        if partOfSpeech == POS_VERB {
+            headIndex = 0
+        }
+        for _, inflection := range wn.Inflect(words[headIndex], partOfSpeech) {
+            words[headIndex] = inflection.Form
+            ret = append(ret, Inflection { strings.Join(words, " "), inflection.Kind })
+        }
+        return ret
+    }
+
+    for _, kind := range inflectionKinds[partOfSpeechIndex] {
+        forms, irregular := irregulars[kind]
+        if !irregular {
+            forms = regularInflections(lemma, partOfSpeech, kind)
+        }
+        for _, form := range forms {
+            ret = append(ret, Inflection { matchCase(original, form), kind })
+        }
+    }
+    return ret
+}
+
+// Returns the lower case inflected form with the case of the lemma it
+// shares a prefix with, word by word. (e.g. "Swim", "swam" -> "Swam")
+func matchCase(lemma string, form string) string {
+    lemmaWords := strings.Split(lemma, " ")
+    formWords := strings.Split(form, " ")
+    if len(lemmaWords) > 1 && len(lemmaWords) == len(formWords) {
+        for i, _ := range formWords {
+            formWords[i] = matchCase(lemmaWords[i], formWords[i])
+        }
+        return strings.Join(formWords, " ")
+    }
+    lowered := strings.ToLower(lemma)
+    if len(lowered) != len(lemma) {
+        return form
+    }
+    n := 0
+    for n < len(form) && n < len(lowered) && form[n] == lowered[n] {
+        n++
+    }
+    for n > 0 && n < len(form) && !utf8.RuneStart(form[n]) {
+        n--
+    }
+    return lemma[:n] + form[n:]
+}
+
+// Returns the first inflection of the given kind, or the lemma if there is
+// none.
+func (wn *WN) inflectAs(lemma string, partOfSpeech int, kind int) string {
+    for _, inflection := range wn.Inflect(lemma, partOfSpeech) {
+        if inflection.Kind == kind {
+            return inflection.Form
+        }
+    }
+    return lemma
+}
+
+// Guesses the kind of an irregular form from the exception lists.
+func irregularInflectionKind(form string, lemma string, partOfSpeech int) int {
+    switch partOfSpeech {
+    case POS_NOUN:
+        return INFLECTION_PLURAL
+    case POS_VERB:
+        if strings.HasSuffix(form, "ing") {
+            return INFLECTION_PRESENT_PARTICIPLE
+        }
+        if form == thirdPersonSingular(lemma) || form == lemma + "es" {
+            return INFLECTION_THIRD_PERSON_SINGULAR
+        }
+        if lemma == "be" && (form == "am" || form == "are" || form == "art") {
+            return INFLECTION_PRESENT
+        }
+        return INFLECTION_PAST
+    default:
+        if strings.HasSuffix(form, "st") {
+            return INFLECTION_SUPERLATIVE
+        }
+        return INFLECTION_COMPARATIVE
+    }
+}
+
+func regularInflections(lemma string, partOfSpeech int, kind int) []string {
+    switch kind {
+    case INFLECTION_PLURAL:
+        return []string { pluralNoun(lemma) }
+    case INFLECTION_THIRD_PERSON_SINGULAR:
+        return []string { thirdPersonSingular(lemma) }
+    case INFLECTION_PAST:
+        return []string { pastTense(lemma) }
+    case INFLECTION_PRESENT_PARTICIPLE:
+        return []string { presentParticiple(lemma) }
+    case INFLECTION_COMPARATIVE, INFLECTION_SUPERLATIVE:
+        // only short adjectives are compared with -er and -est
+        if partOfSpeech == POS_ADVERB || strings.ContainsAny(lemma, " -") {
+            return nil
+        }
+        syllables := countSyllables(lemma)
+        if syllables > 2 || (syllables == 2 && !endsInConsonantY(lemma)) {
+            return nil
+        }
+        if kind == INFLECTION_COMPARATIVE {
+            return []string { compareAdjective(lemma, "er") }
+        }
+        return []string { compareAdjective(lemma, "est") }
+    }
+    return nil
+}
+
+// irregular plurals such as "men" come from noun.exc, so "human" is "humans"
+func pluralNoun(noun string) string {
+    for _, sibilant := range []string { "s", "x", "z", "ch", "sh" } {
+        if strings.HasSuffix(noun, sibilant) {
+            return noun + "es"
+        }
+    }
+    if endsInConsonantY(noun) {
+        return noun[:len(noun) - 1] + "ies"
+    }
+    return noun + "s"
+}
+
+func thirdPersonSingular(verb string) string {
+    switch verb {
+    case "be":
+        return "is"
+    case "have":
+        return "has"
+    }
+    for _, sibilant := range []string { "s", "x", "z", "ch", "sh" } {
+        if strings.HasSuffix(verb, sibilant) {
+            return verb + "es"
+        }
+    }
+    if endsInConsonantY(verb) {
+        return verb[:len(verb) - 1] + "ies"
+    }
+    // e.g. "goes", "does" and "echoes", but "boos"
+    if endsInConsonantO(verb) {
+        return verb + "es"
+    }
+    return verb + "s"
+}
+
+func pastTense(verb string) string {
+    if strings.HasSuffix(verb, "e") {
+        return verb + "d"
+    }
+    if endsInConsonantY(verb) {
+        return verb[:len(verb) - 1] + "ied"
+    }
+    return verb + "ed"
+}
+
+func presentParticiple(verb string) string {
+    if strings.HasSuffix(verb, "ie") {
+        return verb[:len(verb) - 2] + "ying"
+    }
+    if strings.HasSuffix(verb, "e") && len(verb) > 2 &&
+        !strings.HasSuffix(verb, "ee") && !strings.HasSuffix(verb, "ye") && !strings.HasSuffix(verb, "oe") {
+        return verb[:len(verb) - 1] + "ing"
+    }
+    return verb + "ing"
+}
+
+// suffix is "er" or "est"
+func compareAdjective(adjective string, suffix string) string {
+    if strings.HasSuffix(adjective, "e") {
+        return adjective + suffix[1:]
+    }
+    if endsInConsonantY(adjective) {
+        return adjective[:len(adjective) - 1] + "i" + suffix
+    }
+    return adjective + suffix
+}
+
+func endsInConsonantY(word string) bool {
+    if len(word) < 2 || word[len(word) - 1] != 'y' {
+        return false
+    }
+    return !strings.ContainsRune("aeiou", rune(word[len(word) - 2]))
+}
+
+func endsInConsonantO(word string) bool {
+    if len(word) < 2 || word[len(word) - 1] != 'o' {
+        return false
+    }
+    return !strings.ContainsRune("aeiou", rune(word[len(word) - 2]))
+}
+
+// counts the groups of vowels, not counting a final silent e
+func countSyllables(word string) int {
+    word = strings.TrimSuffix(word, "e")
+    count := 0
+    inVowels := false
+    for _, c := range word {
+        isVowel := strings.ContainsRune("aeiouy", c)
+        if isVowel && !inVowels {
+            count++
+        }
+        inVowels = isVowel
+    }
+    return count
+}
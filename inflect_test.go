@@ -0,0 +1,73 @@
+package gown
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestInflect(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    tests := []struct {
+        lemma string
+        pos int
+        expected []Inflection
+    } {
+        { "swim", POS_VERB, []Inflection {
+            { "swims", INFLECTION_THIRD_PERSON_SINGULAR },
+            { "swam", INFLECTION_PAST },
+            { "swum", INFLECTION_PAST },
+            { "swimming", INFLECTION_PRESENT_PARTICIPLE },
+        } },
+        { "walk", POS_VERB, []Inflection {
+            { "walks", INFLECTION_THIRD_PERSON_SINGULAR },
+            { "walked", INFLECTION_PAST },
+            { "walking", INFLECTION_PRESENT_PARTICIPLE },
+        } },
+        { "give up", POS_VERB, []Inflection {
+            { "gives up", INFLECTION_THIRD_PERSON_SINGULAR },
+            { "gave up", INFLECTION_PAST },
+            { "given up", INFLECTION_PAST },
+            { "giving up", INFLECTION_PRESENT_PARTICIPLE },
+        } },
+        { "Swim", POS_VERB, []Inflection {
+            { "Swims", INFLECTION_THIRD_PERSON_SINGULAR },
+            { "Swam", INFLECTION_PAST },
+            { "Swum", INFLECTION_PAST },
+            { "Swimming", INFLECTION_PRESENT_PARTICIPLE },
+        } },
+        { "echo", POS_VERB, []Inflection {
+            { "echoes", INFLECTION_THIRD_PERSON_SINGULAR },
+            { "echoed", INFLECTION_PAST },
+            { "echoing", INFLECTION_PRESENT_PARTICIPLE },
+        } },
+        { "boo", POS_VERB, []Inflection {
+            { "boos", INFLECTION_THIRD_PERSON_SINGULAR },
+            { "booed", INFLECTION_PAST },
+            { "booing", INFLECTION_PRESENT_PARTICIPLE },
+        } },
+        { "Mars", POS_NOUN, []Inflection { { "Marses", INFLECTION_PLURAL } } },
+        { "Attorney General", POS_NOUN, []Inflection { { "Attorneys General", INFLECTION_PLURAL } } },
+        { "child", POS_NOUN, []Inflection { { "children", INFLECTION_PLURAL } } },
+        { "human", POS_NOUN, []Inflection { { "humans", INFLECTION_PLURAL } } },
+        { "German", POS_NOUN, []Inflection { { "Germans", INFLECTION_PLURAL } } },
+        { "box", POS_NOUN, []Inflection { { "boxes", INFLECTION_PLURAL } } },
+        { "attorney general", POS_NOUN, []Inflection { { "attorneys general", INFLECTION_PLURAL } } },
+        { "computing machine", POS_NOUN, []Inflection { { "computing machines", INFLECTION_PLURAL } } },
+        { "good", POS_ADJECTIVE, []Inflection {
+            { "better", INFLECTION_COMPARATIVE },
+            { "best", INFLECTION_SUPERLATIVE },
+        } },
+        { "soggy", POS_ADJECTIVE, []Inflection {
+            { "soggier", INFLECTION_COMPARATIVE },
+            { "soggiest", INFLECTION_SUPERLATIVE },
+        } },
+        { "waterless", POS_ADJECTIVE, []Inflection {} },
+    }
+
+    for _, test := range tests {
+        actual := wn.Inflect(test.lemma, test.pos)
+        if !reflect.DeepEqual(actual, test.expected) {
+            t.Errorf("for %s/%d expected %v but got %v\n", test.lemma, test.pos, test.expected, actual)
+        }
+    }
+}
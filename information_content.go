@@ -0,0 +1,238 @@
+package gown
+
+import (
+    "fmt"
+    "io"
+    "io/fs"
+    "math"
+    "os"
+    "path/filepath"
+    "strconv"
+)
+
+/*
+Information content (IC) of a synset is -log(p), where p is the probability
+of encountering an instance of the synset, or of any of its hyponyms, in a
+corpus. The standard IC files (ic-brown.dat, ic-semcor.dat, ...) distributed
+with WordNet::Similarity and NLTK have a header line followed by lines of:
+synset_offset+pos_tag  count  [ROOT]
+where the count includes the counts of all hyponyms. The counts of the lines
+marked ROOT are summed to get the total for the part of speech.
+*/
+
+type InformationContent struct {
+    counts map[int]map[int]float64 // pos -> synset offset -> count. offset 0 holds the total.
+}
+
+// Reads an information content file such as ic-brown.dat.
+func LoadInformationContent(icFilename string) (*InformationContent, error) {
+    return LoadInformationContentFS(os.DirFS(filepath.Dir(icFilename)), filepath.Base(icFilename))
+}
+
+// Like LoadInformationContent, but reads the file from fsys.
+func LoadInformationContentFS(fsys fs.FS, icFilename string) (*InformationContent, error) {
+    ic := newInformationContent()
+
+    infile, err := openDictFile(fsys, icFilename)
+    if err != nil {
+        return nil, fmt.Errorf("can't open %s: %v", icFilename, err)
+    }
+    defer infile.Close()
+
+    lr := newLineReader(infile, icFilename)
+    for {
+        line, err := lr.next()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, err
+        }
+        if lr.lineNumber == 1 {
+            // header line (e.g. "wnver::eOS9lXC6GvMWznF1wkZofDdtbBU")
+            continue
+        }
+        f := lr.fields(line)
+        if !f.more() {
+            continue
+        }
+        offsetField := f.str("synset_offset")
+        count := f.float("count")
+        root := f.more() && f.str("root") == "ROOT"
+        if f.err != nil {
+            return nil, f.err
+        }
+        if len(offsetField) < 2 {
+            return nil, lr.fieldError("synset_offset", offsetField)
+        }
+        pos := oneCharPosTagToPosId(offsetField[len(offsetField) - 1:])
+        offset, err := strconv.Atoi(offsetField[:len(offsetField) - 1])
+        if err != nil || pos == POS_UNSUPPORTED {
+            return nil, lr.fieldError("synset_offset", offsetField)
+        }
+        posCounts := ic.posCounts(pos)
+        if root {
+            posCounts[0] += count
+        }
+        if count != 0 {
+            posCounts[offset] = count
+        }
+    }
+
+    return ic, nil
+}
+
+// Computes information content from word frequencies of a corpus. Each
+// word's count is added to every synset containing the word and to all of
+// their hypernyms. If divideAmongSenses is true, the count is split evenly
+// among the synsets of the word. smoothing is added to the count of every
+// synset (use 1.0 for add-one smoothing, or 0 for none).
+func (wn *WN) ComputeInformationContent(wordCounts map[string]float64, smoothing float64, divideAmongSenses bool) *InformationContent {
+    ic := newInformationContent()
+
+    if smoothing > 0 {
+        for _, pos := range []int { POS_NOUN, POS_VERB, POS_ADJECTIVE, POS_ADVERB } {
+            ic.posCounts(pos)[0] = smoothing
+        }
+        for synset := range wn.Iter() {
+            k := getSynsetKey(synset)
+            ic.posCounts(k.pos)[k.offset] = smoothing
+        }
+    }
+
+    for word, count := range wordCounts {
+        synsets := []*Synset{}
+        for _, pos := range []int { POS_NOUN, POS_VERB, POS_ADJECTIVE, POS_ADVERB } {
+            dataIndexEntry := wn.LookupWithPartOfSpeech(word, pos)
+            if dataIndexEntry == nil {
+                continue
+            }
+            for _, synsetOffset := range dataIndexEntry.SynsetOffsets {
+                synset := wn.GetSynset(pos, synsetOffset)
+                if synset != nil {
+                    synsets = append(synsets, synset)
+                }
+            }
+        }
+        if len(synsets) == 0 {
+            continue
+        }
+
+        weight := count
+        if divideAmongSenses {
+            weight /= float64(len(synsets))
+        }
+        for _, synset := range synsets {
+            pos := getSynsetKey(synset).pos
+            posCounts := ic.posCounts(pos)
+            for k, _ := range wn.hypernymDistances(synset, false) {
+                posCounts[k.offset] += weight
+            }
+            posCounts[0] += weight
+        }
+    }
+
+    return ic
+}
+
+func newInformationContent() *InformationContent {
+    return &InformationContent {
+        counts: map[int]map[int]float64{},
+    }
+}
+
+func (ic *InformationContent) posCounts(pos int) map[int]float64 {
+    if pos == POS_ADJECTIVE_SATELLITE {
+        pos = POS_ADJECTIVE
+    }
+    posCounts, exists := ic.counts[pos]
+    if !exists {
+        posCounts = map[int]float64{}
+        ic.counts[pos] = posCounts
+    }
+    return posCounts
+}
+
+// Returns -log(p(synset)). Synsets that were never seen have an infinite
+// information content.
+func (ic *InformationContent) InformationContent(s *Synset) float64 {
+    k := getSynsetKey(s)
+    posCounts, exists := ic.counts[k.pos]
+    if !exists || posCounts[0] == 0 {
+        return math.Inf(1)
+    }
+    count := posCounts[k.offset]
+    if count == 0 {
+        return math.Inf(1)
+    }
+    return -math.Log(count / posCounts[0])
+}
+
+// Returns the information content of both synsets and of their most
+// informative common hypernym. The synsets must have the same part of
+// speech.
+func (wn *WN) lcsInformationContent(s1 *Synset, s2 *Synset, ic *InformationContent) (float64, float64, float64, bool) {
+    if getSynsetKey(s1).pos != getSynsetKey(s2).pos {
+        return 0, 0, 0, false
+    }
+    ic1 := ic.InformationContent(s1)
+    ic2 := ic.InformationContent(s2)
+
+    distances2 := wn.hypernymDistances(s2, false)
+    lcsIc := 0.0
+    for k, _ := range wn.hypernymDistances(s1, false) {
+        if _, common := distances2[k]; !common {
+            continue
+        }
+        subsumer := wn.GetSynset(k.pos, k.offset)
+        if subsumer == nil {
+            continue
+        }
+        subsumerIc := ic.InformationContent(subsumer)
+        if subsumerIc > lcsIc {
+            lcsIc = subsumerIc
+        }
+    }
+    return ic1, ic2, lcsIc, true
+}
+
+// Returns the Resnik similarity, the information content of the most
+// informative common hypernym. The second return value is false if the
+// synsets have different parts of speech.
+func (wn *WN) ResnikSimilarity(s1 *Synset, s2 *Synset, ic *InformationContent) (float64, bool) {
+    _, _, lcsIc, ok := wn.lcsInformationContent(s1, s2, ic)
+    return lcsIc, ok
+}
+
+// Returns the Lin similarity, 2 * IC(lcs) / (IC(s1) + IC(s2)). The second
+// return value is false if the synsets have different parts of speech.
+func (wn *WN) LinSimilarity(s1 *Synset, s2 *Synset, ic *InformationContent) (float64, bool) {
+    ic1, ic2, lcsIc, ok := wn.lcsInformationContent(s1, s2, ic)
+    if !ok {
+        return 0, false
+    }
+    return (2.0 * lcsIc) / (ic1 + ic2), true
+}
+
+// Returns the Jiang-Conrath similarity, 1 / (IC(s1) + IC(s2) - 2 * IC(lcs)).
+// Identical synsets have an infinite similarity, and as in NLTK, a synset
+// with no information content (a root, or one never seen) has a similarity
+// of 0 to every other synset. The second return value is false if the
+// synsets have different parts of speech.
+func (wn *WN) JiangConrathSimilarity(s1 *Synset, s2 *Synset, ic *InformationContent) (float64, bool) {
+    if getSynsetKey(s1) == getSynsetKey(s2) {
+        return math.Inf(1), true
+    }
+    ic1, ic2, lcsIc, ok := wn.lcsInformationContent(s1, s2, ic)
+    if !ok {
+        return 0, false
+    }
+    if ic1 == 0 || ic2 == 0 || math.IsInf(ic1, 1) || math.IsInf(ic2, 1) {
+        return 0, true
+    }
+    difference := ic1 + ic2 - 2 * lcsIc
+    if difference == 0 {
+        return math.Inf(1), true
+    }
+    return 1.0 / difference, true
+}
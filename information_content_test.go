@@ -0,0 +1,132 @@
+package gown
+
+import (
+    "errors"
+    "math"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestInformationContentSimilarity(t *testing.T) {
+    wn, dictDir := loadSystemWordNet(t)
+    icFilename := "ic-brown.dat"
+    if !dictFileExists(os.DirFS(dictDir), icFilename) {
+        t.Skipf("%s not found in %s", icFilename, dictDir)
+    }
+    ic, err := LoadInformationContentFS(os.DirFS(dictDir), icFilename)
+    if err != nil {
+        t.Fatalf("failed to read %s: %v", icFilename, err)
+    }
+
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+    cat := wn.LookupWithPartOfSpeechAndSense("cat", POS_NOUN, 1).GetSynsetPtr()
+
+    // reference values from NLTK on WordNet 3.0 with ic-brown.dat
+    resnik, _ := wn.ResnikSimilarity(dog, cat, ic)
+    if math.Abs(resnik - 7.911666509036577) > 1e-9 {
+        t.Errorf("resnik: expected 7.911666509036577 but got %v", resnik)
+    }
+    jcn, _ := wn.JiangConrathSimilarity(dog, cat, ic)
+    if math.Abs(jcn - 0.4497755161516617) > 1e-9 {
+        t.Errorf("jcn: expected 0.4497755161516617 but got %v", jcn)
+    }
+}
+
+func TestComputeInformationContent(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+
+    ic := wn.ComputeInformationContent(map[string]float64 { "dog": 10, "cat": 5, "computer": 1 }, 1.0, true)
+    entity := wn.LookupWithPartOfSpeechAndSense("entity", POS_NOUN, 1).GetSynsetPtr()
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+    cat := wn.LookupWithPartOfSpeechAndSense("cat", POS_NOUN, 1).GetSynsetPtr()
+
+    if actual := ic.InformationContent(entity); actual != 0 {
+        t.Errorf("expected the root to have no information content, but got %v", actual)
+    }
+    if ic.InformationContent(dog) >= ic.InformationContent(cat) {
+        t.Errorf("expected \"dog\" to be more frequent than \"cat\"")
+    }
+    lin, ok := wn.LinSimilarity(dog, cat, ic)
+    if !ok || lin <= 0 || lin >= 1 {
+        t.Errorf("expected a lin similarity between 0 and 1, but got %v", lin)
+    }
+}
+
+func TestLoadInformationContent(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    ic, err := LoadInformationContent(filepath.Join("testdata", "ic-test.dat"))
+    if err != nil {
+        t.Fatalf("failed to read ic-test.dat: %v", err)
+    }
+
+    entity := wn.LookupWithPartOfSpeechAndSense("entity", POS_NOUN, 1).GetSynsetPtr()
+    carnivore := wn.LookupWithPartOfSpeechAndSense("carnivore", POS_NOUN, 1).GetSynsetPtr()
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+    cat := wn.LookupWithPartOfSpeechAndSense("cat", POS_NOUN, 1).GetSynsetPtr()
+    octopus := wn.LookupWithPartOfSpeechAndSense("octopus", POS_NOUN, 1).GetSynsetPtr()
+    travel := wn.LookupWithPartOfSpeechAndSense("travel", POS_VERB, 1).GetSynsetPtr()
+    walk := wn.LookupWithPartOfSpeechAndSense("walk", POS_VERB, 1).GetSynsetPtr()
+
+    // the ROOT lines are the totals of each part of speech, 100 nouns and
+    // 30 + 10 verbs
+    testCases := []struct {
+        synset *Synset
+        expected float64
+    }{
+        { entity, 0 },
+        { carnivore, math.Log(5) },
+        { dog, math.Log(10) },
+        { cat, math.Log(20) },
+        { octopus, math.Inf(1) },   // counted 0 times
+        { travel, math.Log(4.0 / 3.0) },
+        { walk, math.Inf(1) },      // not in the file
+    }
+    for _, testCase := range testCases {
+        actual := ic.InformationContent(testCase.synset)
+        if math.Abs(actual - testCase.expected) > 1e-9 && actual != testCase.expected {
+            t.Errorf("expected the information content of %v to be %v, but got %v", testCase.synset.Words, testCase.expected, actual)
+        }
+    }
+
+    resnik, _ := wn.ResnikSimilarity(dog, cat, ic)
+    if math.Abs(resnik - math.Log(5)) > 1e-9 {
+        t.Errorf("resnik: expected %v but got %v", math.Log(5), resnik)
+    }
+    jcn, _ := wn.JiangConrathSimilarity(dog, cat, ic)
+    if math.Abs(jcn - 1 / math.Log(8)) > 1e-9 {
+        t.Errorf("jcn: expected %v but got %v", 1 / math.Log(8), jcn)
+    }
+    // synsets without information content aren't similar to anything
+    for _, other := range []*Synset { entity, octopus } {
+        if jcn, ok := wn.JiangConrathSimilarity(dog, other, ic); !ok || jcn != 0 {
+            t.Errorf("jcn: expected 0 between dog and %v, but got %v", other.Words, jcn)
+        }
+    }
+    if jcn, ok := wn.JiangConrathSimilarity(dog, dog, ic); !ok || !math.IsInf(jcn, 1) {
+        t.Errorf("jcn: expected dog to be infinitely similar to itself, but got %v", jcn)
+    }
+}
+
+func TestLoadInformationContentParseError(t *testing.T) {
+    dir := t.TempDir()
+    lines := "wnver::eOS9lXC6GvMWznF1wkZofDdtbBU\n" +
+        "153n 100 ROOT\n" +
+        "2071x 10\n"
+    err := os.WriteFile(filepath.Join(dir, "ic-bad.dat"), []byte(lines), 0644)
+    if err != nil {
+        t.Fatalf("can't write ic-bad.dat: %v", err)
+    }
+
+    _, err = LoadInformationContentFS(os.DirFS(dir), "ic-bad.dat")
+    var parseErr *ParseError
+    if !errors.As(err, &parseErr) {
+        t.Fatalf("expected a *ParseError, but got %v", err)
+    }
+    if parseErr.Line != 3 || parseErr.Field != "synset_offset" || parseErr.Value != "2071x" {
+        t.Errorf("unexpected error %v", parseErr)
+    }
+    if !errors.Is(err, ErrMalformedField) {
+        t.Errorf("expected ErrMalformedField, but got %v", parseErr.Err)
+    }
+}
@@ -0,0 +1,508 @@
+package gown
+
+import (
+    "bytes"
+    "container/list"
+    "fmt"
+    "io"
+    "io/fs"
+    "strings"
+    "sync"
+)
+
+/*
+In lazy mode nothing is parsed up front. Synset offsets are byte offsets into
+the data.POS files, so GetSynset seeks to the offset and parses a single
+line. The index.POS and index.sense files are sorted, so lemmas are found by
+binary search over the file. Recently used synsets are kept in a bounded LRU
+cache.
+*/
+
+// the number of synsets kept in the cache if LoadOptions.SynsetCacheSize is 0
+const DEFAULT_SYNSET_CACHE_SIZE int = 10000
+
+type LoadOptions struct {
+    // Parse synsets and index entries on demand instead of loading them all
+    // into memory. PosIndicies is left empty in lazy mode.
+    Lazy bool
+    // The maximum number of parsed synsets cached in lazy mode.
+    SynsetCacheSize int
+    // Call AddInverseRelationships after loading.
+    InverseRelationships bool
+    // Call ConnectVerbGroups after loading.
+    ConnectVerbGroups bool
+    // Call BuildHypernymIndex after loading.
+    HypernymIndex bool
+}
+
+type lazyData struct {
+    source lazySource
+    cache *synsetCache
+    overlayLock sync.RWMutex
+    overlay map[synsetKey][]RelationshipEdge // edges added to the synsets
+}
+
+// Where a lazily loaded WN reads its synsets and index entries: the
+// dictionary files, or a snapshot.
+type lazySource interface {
+    readSynset(pos int, synsetOffset int) (Synset, bool)
+    readIndexEntry(pos int, lemma string) (DataIndexEntry, bool)
+    readSenses(wn *WN, lemma string) []SenseIndexEntry
+    // These call fn with each synset, index entry or sense in turn,
+    // stopping early if it returns false.
+    eachSynset(pos int, fn func(synset Synset) bool)
+    eachIndexEntry(pos int, fn func(pair DataIndexPair) bool)
+    eachSense(wn *WN, fn func(entry SenseIndexEntry) bool)
+    // Returns the index entries of the lemmas starting with prefix that
+    // match, in any order. match may be nil to match them all.
+    lemmasWithPrefix(prefix string, match func(lemma string) bool) []DataIndexPair
+    Close() error
+}
+
+// The dictionary files, read in place.
+type dictFiles struct {
+    indexFiles map[int]*dictFileReader
+    dataFiles map[int]*dictFileReader
+    senseIndexFile *dictFileReader
+    closers []io.Closer
+}
+
+// Random access to a dictionary file.
+type dictFileReader struct {
+    name string
+    r io.ReaderAt
+    size int64
+}
+
+func newLazyData(source lazySource, cacheSize int) *lazyData {
+    if cacheSize <= 0 {
+        cacheSize = DEFAULT_SYNSET_CACHE_SIZE
+    }
+    return &lazyData {
+        source: source,
+        cache: newSynsetCache(cacheSize),
+    }
+}
+
+func loadLazyData(fsys fs.FS, cacheSize int) (*lazyData, error) {
+    d := &dictFiles {
+        indexFiles: map[int]*dictFileReader{},
+        dataFiles: map[int]*dictFileReader{},
+    }
+
+    var err error = nil
+    pos_file_names := []string{"", "noun", "verb", "adj", "adv"}
+    for i := 1; i < len(pos_file_names); i++ {
+        d.indexFiles[i], err = d.open(fsys, "index." + pos_file_names[i])
+        if err != nil {
+            d.Close()
+            return nil, err
+        }
+        d.dataFiles[i], err = d.open(fsys, "data." + pos_file_names[i])
+        if err != nil {
+            d.Close()
+            return nil, err
+        }
+    }
+    d.senseIndexFile, err = d.open(fsys, "index.sense")
+    if err != nil {
+        d.Close()
+        return nil, err
+    }
+    return newLazyData(d, cacheSize), nil
+}
+
+// Opens a dictionary file for random access. Files that don't support
+// io.ReaderAt (such as gzip compressed ones) are read into memory.
+func (d *dictFiles) open(fsys fs.FS, name string) (*dictFileReader, error) {
+    infile, err := openDictFile(fsys, name)
+    if err != nil {
+        return nil, fmt.Errorf("can't open %s: %v", name, err)
+    }
+    readerAt, isReaderAt := infile.(io.ReaderAt)
+    statter, isStatter := infile.(interface{ Stat() (fs.FileInfo, error) })
+    if isReaderAt && isStatter {
+        info, err := statter.Stat()
+        if err == nil {
+            d.closers = append(d.closers, infile)
+            return &dictFileReader { name, readerAt, info.Size() }, nil
+        }
+    }
+
+    defer infile.Close()
+    contents, err := io.ReadAll(infile)
+    if err != nil {
+        return nil, &ParseError { Filename: name, Err: err }
+    }
+    return &dictFileReader { name, bytes.NewReader(contents), int64(len(contents)) }, nil
+}
+
+func (d *dictFiles) Close() error {
+    var err error = nil
+    for _, closer := range d.closers {
+        if cerr := closer.Close(); err == nil {
+            err = cerr
+        }
+    }
+    d.closers = nil
+    return err
+}
+
+func (d *dictFiles) readSynset(pos int, synsetOffset int) (Synset, bool) {
+    dataFile, exists := d.dataFiles[pos]
+    if !exists || synsetOffset < 0 || int64(synsetOffset) >= dataFile.size {
+        return Synset{}, false
+    }
+    line, _, err := dataFile.readLineAt(int64(synsetOffset))
+    if err != nil || isCommentLine(line) {
+        return Synset{}, false
+    }
+    synset, err := parsePosDataLine(dataFile.name, 0, line)
+    if err != nil || synset.SynsetOffset != synsetOffset {
+        return Synset{}, false
+    }
+    return synset, true
+}
+
+func (d *dictFiles) readIndexEntry(pos int, lemma string) (DataIndexEntry, bool) {
+    indexFile, exists := d.indexFiles[pos]
+    if !exists {
+        return DataIndexEntry{}, false
+    }
+    key := writeStoredLemma(strings.ToLower(lemma))
+    line, found := indexFile.findLine(key)
+    if !found {
+        return DataIndexEntry{}, false
+    }
+    _, entry, err := parsePosIndexLine(newFieldReader(indexFile.name, 0, line))
+    if err != nil {
+        return DataIndexEntry{}, false
+    }
+    return entry, true
+}
+
+func (d *dictFiles) readSenses(wn *WN, lemma string) []SenseIndexEntry {
+    ret := []SenseIndexEntry{}
+    for _, line := range d.senseIndexFile.linesWithPrefix(writeStoredLemma(lemma) + "%") {
+        entry, err := parseSenseIndexLine(wn, newFieldReader(d.senseIndexFile.name, 0, line))
+        if err == nil {
+            ret = append(ret, entry)
+        }
+    }
+    return ret
+}
+
+func (d *dictFiles) eachSynset(pos int, fn func(synset Synset) bool) {
+    dataFile, exists := d.dataFiles[pos]
+    if !exists {
+        return
+    }
+    dataFile.eachLine(func(line string, lineNumber int) bool {
+        if isCommentLine(line) {
+            return true
+        }
+        synset, err := parsePosDataLine(dataFile.name, lineNumber, line)
+        return err != nil || fn(synset)
+    })
+}
+
+func (d *dictFiles) eachIndexEntry(pos int, fn func(pair DataIndexPair) bool) {
+    indexFile, exists := d.indexFiles[pos]
+    if !exists {
+        return
+    }
+    indexFile.eachLine(func(line string, lineNumber int) bool {
+        if isCommentLine(line) {
+            return true
+        }
+        lemma, entry, err := parsePosIndexLine(newFieldReader(indexFile.name, lineNumber, line))
+        return err != nil || fn(DataIndexPair{ lemma, entry })
+    })
+}
+
+func (d *dictFiles) eachSense(wn *WN, fn func(entry SenseIndexEntry) bool) {
+    d.senseIndexFile.eachLine(func(line string, lineNumber int) bool {
+        entry, err := parseSenseIndexLine(wn, newFieldReader(d.senseIndexFile.name, lineNumber, line))
+        return err != nil || fn(entry)
+    })
+}
+
+func (l *lazyData) Close() error {
+    return l.source.Close()
+}
+
+func (l *lazyData) getSynset(pos int, synsetOffset int) *Synset {
+    k := synsetKey { pos, synsetOffset }
+    // held until the synset is cached, so it can't cache a synset missing
+    // edges appended meanwhile
+    l.overlayLock.RLock()
+    defer l.overlayLock.RUnlock()
+    synset, exists := l.cache.get(k)
+    if !exists {
+        synset, exists = l.source.readSynset(pos, synsetOffset)
+        if !exists {
+            return nil
+        }
+        l.applyOverlay(&synset)
+        l.cache.add(k, synset)
+    }
+    return &synset
+}
+
+func (l *lazyData) lookupIndex(pos int, lemma string) *DataIndexEntry {
+    entry, exists := l.source.readIndexEntry(pos, lemma)
+    if !exists {
+        return nil
+    }
+    return &entry
+}
+
+func (l *lazyData) senses(wn *WN, lemma string) []SenseIndexEntry {
+    return l.source.readSenses(wn, lemma)
+}
+
+func (l *lazyData) traverseDataIndex(pos int) <-chan DataIndexPair {
+    if pos < POS_NOUN || pos > POS_ADVERB {
+        return nil
+    }
+    out := make(chan DataIndexPair)
+    go func() {
+        l.source.eachIndexEntry(pos, func(pair DataIndexPair) bool {
+            out <- pair
+            return true
+        })
+        close(out)
+    }()
+    return out
+}
+
+// Adds edges to the synsets.
+func (l *lazyData) appendOverlay(edges map[synsetKey][]RelationshipEdge) {
+    l.overlayLock.Lock()
+    defer l.overlayLock.Unlock()
+    if l.overlay == nil {
+        l.overlay = map[synsetKey][]RelationshipEdge{}
+    }
+    for k, synsetEdges := range edges {
+        l.overlay[k] = append(l.overlay[k], synsetEdges...)
+    }
+    l.cache.clear()
+}
+
+// Adds the edges appended to the synset. The caller holds overlayLock.
+func (l *lazyData) applyOverlay(synset *Synset) {
+    edges := l.overlay[getSynsetKey(synset)]
+    if len(edges) > 0 {
+        relationships := make([]RelationshipEdge, 0, len(synset.Relationships) + len(edges))
+        relationships = append(relationships, synset.Relationships...)
+        synset.Relationships = append(relationships, edges...)
+    }
+}
+
+func (l *lazyData) iter() <-chan *Synset {
+    outChan := make(chan *Synset)
+    go func() {
+        for synset := range l.iterRaw() {
+            l.overlayLock.RLock()
+            l.applyOverlay(synset)
+            l.overlayLock.RUnlock()
+            outChan <- synset
+        }
+        close(outChan)
+    }()
+    return outChan
+}
+
+// Iterates over the synsets as stored, without the edges added to them.
+func (l *lazyData) iterRaw() <-chan *Synset {
+    outChan := make(chan *Synset)
+    go func() {
+        for pos := POS_NOUN; pos <= POS_ADVERB; pos++ {
+            l.source.eachSynset(pos, func(synset Synset) bool {
+                outChan <- &synset
+                return true
+            })
+        }
+        close(outChan)
+    }()
+    return outChan
+}
+
+func (l *lazyData) iterSenses(wn *WN) <-chan *SenseIndexEntry {
+    outchan := make(chan *SenseIndexEntry)
+    go func() {
+        l.source.eachSense(wn, func(entry SenseIndexEntry) bool {
+            outchan <- &entry
+            return true
+        })
+        close(outchan)
+    }()
+    return outchan
+}
+
+// Calls fn with each line of the file, stopping early if it returns false.
+func (d *dictFileReader) eachLine(fn func(line string, lineNumber int) bool) {
+    lr := newLineReader(io.NewSectionReader(d.r, 0, d.size), d.name)
+    for {
+        line, err := lr.next()
+        if err != nil {
+            return
+        }
+        if !fn(line, lr.lineNumber) {
+            return
+        }
+    }
+}
+
+// Reads the line starting at offset. Returns the line, without its line
+// terminator, and the offset of the following line.
+func (d *dictFileReader) readLineAt(offset int64) (string, int64, error) {
+    if offset >= d.size {
+        return "", d.size, io.EOF
+    }
+    line := []byte{}
+    chunk := make([]byte, 512)
+    position := offset
+    for position < d.size {
+        n, err := d.r.ReadAt(chunk, position)
+        newlineIndex := bytes.IndexByte(chunk[:n], '\n')
+        if newlineIndex >= 0 {
+            line = append(line, chunk[:newlineIndex]...)
+            return strings.TrimRight(string(line), "\r"), position + int64(newlineIndex) + 1, nil
+        }
+        line = append(line, chunk[:n]...)
+        position += int64(n)
+        if err != nil && err != io.EOF {
+            return "", 0, err
+        }
+        if n == 0 {
+            break
+        }
+    }
+    return string(line), d.size, nil
+}
+
+// Returns the offset of the first line starting at or after position.
+func (d *dictFileReader) lineStart(position int64) int64 {
+    if position <= 0 {
+        return 0
+    }
+    _, next, err := d.readLineAt(position - 1)
+    if err != nil {
+        return d.size
+    }
+    return next
+}
+
+// The sort key of a line is its first field. The license at the top of the
+// file sorts before everything else.
+func lineKey(line string) string {
+    if strings.HasPrefix(line, " ") {
+        return ""
+    }
+    spaceIndex := strings.IndexByte(line, ' ')
+    if spaceIndex < 0 {
+        return line
+    }
+    return line[:spaceIndex]
+}
+
+// Binary searches the sorted file for the first line with a key >= key.
+// Returns its offset, or the file size if there is none.
+func (d *dictFileReader) search(key string) int64 {
+    low, high := int64(0), d.size
+    for low < high {
+        middle := low + (high - low) / 2
+        start := d.lineStart(middle)
+        if start >= d.size {
+            high = middle
+            continue
+        }
+        line, _, err := d.readLineAt(start)
+        if err != nil || lineKey(line) >= key {
+            high = middle
+        } else {
+            low = middle + 1
+        }
+    }
+    return d.lineStart(low)
+}
+
+// Returns the line with exactly the given key.
+func (d *dictFileReader) findLine(key string) (string, bool) {
+    line, _, err := d.readLineAt(d.search(key))
+    if err != nil || lineKey(line) != key {
+        return "", false
+    }
+    return line, true
+}
+
+// Returns all the consecutive lines whose keys start with prefix.
+func (d *dictFileReader) linesWithPrefix(prefix string) []string {
+    ret := []string{}
+    offset := d.search(prefix)
+    for offset < d.size {
+        line, next, err := d.readLineAt(offset)
+        if err != nil || !strings.HasPrefix(lineKey(line), prefix) {
+            break
+        }
+        ret = append(ret, line)
+        offset = next
+    }
+    return ret
+}
+
+// A least recently used cache of parsed synsets.
+type synsetCache struct {
+    lock sync.Mutex
+    capacity int
+    entries map[synsetKey]*list.Element
+    order *list.List // most recently used first
+}
+
+type synsetCacheEntry struct {
+    key synsetKey
+    synset Synset
+}
+
+func newSynsetCache(capacity int) *synsetCache {
+    return &synsetCache {
+        capacity: capacity,
+        entries: map[synsetKey]*list.Element{},
+        order: list.New(),
+    }
+}
+
+func (c *synsetCache) get(k synsetKey) (Synset, bool) {
+    c.lock.Lock()
+    defer c.lock.Unlock()
+    element, exists := c.entries[k]
+    if !exists {
+        return Synset{}, false
+    }
+    c.order.MoveToFront(element)
+    return element.Value.(*synsetCacheEntry).synset, true
+}
+
+func (c *synsetCache) clear() {
+    c.lock.Lock()
+    defer c.lock.Unlock()
+    c.entries = map[synsetKey]*list.Element{}
+    c.order.Init()
+}
+
+func (c *synsetCache) add(k synsetKey, synset Synset) {
+    c.lock.Lock()
+    defer c.lock.Unlock()
+    if element, exists := c.entries[k]; exists {
+        element.Value.(*synsetCacheEntry).synset = synset
+        c.order.MoveToFront(element)
+        return
+    }
+    c.entries[k] = c.order.PushFront(&synsetCacheEntry { k, synset })
+    for c.order.Len() > c.capacity {
+        oldest := c.order.Back()
+        c.order.Remove(oldest)
+        delete(c.entries, oldest.Value.(*synsetCacheEntry).key)
+    }
+}
@@ -0,0 +1,71 @@
+package gown
+
+import (
+    "os"
+    "reflect"
+    "testing"
+)
+
+func TestLoadWordNetLazy(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    lazyWn := loadTestWordNet(t, LoadOptions { Lazy: true, SynsetCacheSize: 4 })
+
+    for _, lemma := range []string { "computer", "dog", "Mars", "attorney general", "swim", "wet", "well", "nonexistentword" } {
+        senses := wn.Lookup(lemma)
+        lazySenses := lazyWn.Lookup(lemma)
+        if len(senses) != len(lazySenses) {
+            t.Errorf("%q: %d senses, but %d lazily", lemma, len(senses), len(lazySenses))
+            continue
+        }
+        found := map[synsetKey]bool{}
+        for _, sense := range senses {
+            found[synsetKey { sense.PartOfSpeech, sense.SynsetOffset }] = true
+        }
+        for _, sense := range lazySenses {
+            if !found[synsetKey { sense.PartOfSpeech, sense.SynsetOffset }] {
+                t.Errorf("%q: unexpected lazy sense %v", lemma, sense)
+            }
+            if !reflect.DeepEqual(sense.GetSynsetPtr(), wn.GetSynset(sense.PartOfSpeech, sense.SynsetOffset)) {
+                t.Errorf("%q: lazy sense has the wrong synset", lemma)
+            }
+        }
+
+        for _, pos := range []int { POS_NOUN, POS_VERB, POS_ADJECTIVE, POS_ADVERB } {
+            entry := wn.LookupWithPartOfSpeech(lemma, pos)
+            lazyEntry := lazyWn.LookupWithPartOfSpeech(lemma, pos)
+            if !reflect.DeepEqual(entry, lazyEntry) {
+                t.Errorf("%q: index entries differ: %v vs %v", lemma, entry, lazyEntry)
+            }
+            if entry == nil {
+                continue
+            }
+            for _, synsetOffset := range entry.SynsetOffsets {
+                synset := wn.GetSynset(pos, synsetOffset)
+                // twice, the second time from the cache
+                for i := 0; i < 2; i++ {
+                    lazySynset := lazyWn.GetSynset(pos, synsetOffset)
+                    if !reflect.DeepEqual(synset, lazySynset) {
+                        t.Errorf("%q: synsets at %d differ: %v vs %v", lemma, synsetOffset, synset, lazySynset)
+                    }
+                }
+            }
+        }
+    }
+
+    if lazyWn.GetSynset(POS_NOUN, 1) != nil {
+        t.Errorf("expected no synset at offset 1")
+    }
+}
+
+func BenchmarkLookupLazy(b *testing.B) {
+    _, dictDir := loadSystemWordNet(b)
+    wn, err := LoadWordNetWithOptions(os.DirFS(dictDir), LoadOptions { Lazy: true })
+    if err != nil {
+        b.Fatalf("can't load WordNet from %s: %v", dictDir, err)
+    }
+    defer wn.Close()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        wn.Lookup("live")
+    }
+}
@@ -0,0 +1,195 @@
+package gown
+
+import (
+    "fmt"
+    "regexp"
+    "sort"
+    "strings"
+)
+
+/*
+Lemma searches find index entries by the form of their lemmas rather than
+an exact match. The lemmas of all parts of speech are kept in one sorted
+list, built at load time, so a prefix search is a binary search followed by
+a scan of the matching range. Lazily loaded databases search the sorted
+index files instead. Lemmas are lower case, with spaces between words.
+*/
+
+type LemmaSearchOptions struct {
+    PartsOfSpeech []int     // only lemmas with one of these parts of speech, or any if empty
+    Offset int              // the number of matches to skip, for paging through them
+    Limit int               // the maximum number of results, or no limit if 0
+}
+
+// Returns the index entries of the lemmas starting with prefix, sorted by
+// lemma and part of speech. (e.g. "comput" finds "computer" and "compute")
+func (wn *WN) PrefixSearch(prefix string, opts LemmaSearchOptions) []DataIndexPair {
+    return wn.searchLemmas(strings.ToLower(prefix), nil, opts)
+}
+
+// Returns the index entries of the lemmas matching a glob pattern, sorted
+// by lemma and part of speech. "*" matches any text, "?" any character and
+// "[...]" any character in the class. (e.g. "comput*", "?at", "[bc]at")
+func (wn *WN) GlobSearch(pattern string, opts LemmaSearchOptions) ([]DataIndexPair, error) {
+    re, prefix, err := globToRegexp(strings.ToLower(pattern))
+    if err != nil {
+        return nil, err
+    }
+    return wn.searchLemmas(prefix, re.MatchString, opts), nil
+}
+
+// Returns the index entries of the lemmas matching a regular expression,
+// sorted by lemma and part of speech. The expression may match any part of
+// the lemma unless it's anchored with ^ and $.
+func (wn *WN) RegexpSearch(re *regexp.Regexp, opts LemmaSearchOptions) []DataIndexPair {
+    return wn.searchLemmas("", re.MatchString, opts)
+}
+
+// Returns a page of the lemmas starting with prefix that match. match may
+// be nil to match them all.
+func (wn *WN) searchLemmas(prefix string, match func(lemma string) bool, opts LemmaSearchOptions) []DataIndexPair {
+    ret := []DataIndexPair{}
+    skipped := 0
+    // returns false once the page is full
+    add := func(pair DataIndexPair) bool {
+        if len(opts.PartsOfSpeech) > 0 {
+            found := false
+            for _, pos := range opts.PartsOfSpeech {
+                if normalizePos(pos) == pair.IndexEntry.PartOfSpeech {
+                    found = true
+                }
+            }
+            if !found {
+                return true
+            }
+        }
+        if match != nil && !match(pair.Lexeme) {
+            return true
+        }
+        if skipped < opts.Offset {
+            skipped++
+            return true
+        }
+        ret = append(ret, pair)
+        return opts.Limit <= 0 || len(ret) < opts.Limit
+    }
+
+    if wn.lazy != nil {
+        for _, pair := range wn.lazy.lemmasWithPrefix(prefix, match) {
+            if !add(pair) {
+                break
+            }
+        }
+        return ret
+    }
+
+    i := sort.Search(len(wn.lemmas), func(i int) bool { return wn.lemmas[i].Lexeme >= prefix })
+    for ; i < len(wn.lemmas) && strings.HasPrefix(wn.lemmas[i].Lexeme, prefix); i++ {
+        if !add(wn.lemmas[i]) {
+            break
+        }
+    }
+    return ret
+}
+
+// Builds the sorted list of lemmas searched by searchLemmas.
+func (wn *WN) buildLemmaList() {
+    lemmas := []DataIndexPair{}
+    for _, index := range wn.PosIndicies {
+        for lemma, entry := range *index {
+            lemmas = append(lemmas, DataIndexPair { lemma, entry })
+        }
+    }
+    sortLemmas(lemmas)
+    wn.lemmas = lemmas
+}
+
+func sortLemmas(lemmas []DataIndexPair) {
+    sort.Slice(lemmas, func(i, j int) bool {
+        if lemmas[i].Lexeme != lemmas[j].Lexeme {
+            return lemmas[i].Lexeme < lemmas[j].Lexeme
+        }
+        return lemmas[i].IndexEntry.PartOfSpeech < lemmas[j].IndexEntry.PartOfSpeech
+    })
+}
+
+// Returns the lemmas starting with prefix that match, sorted. match may be
+// nil to match them all.
+func (l *lazyData) lemmasWithPrefix(prefix string, match func(lemma string) bool) []DataIndexPair {
+    ret := l.source.lemmasWithPrefix(prefix, match)
+    sortLemmas(ret)
+    return ret
+}
+
+func (d *dictFiles) lemmasWithPrefix(prefix string, match func(lemma string) bool) []DataIndexPair {
+    ret := []DataIndexPair{}
+    storedPrefix := writeStoredLemma(prefix)
+    for _, indexFile := range d.indexFiles {
+        addLine := func(line string, lineNumber int) bool {
+            key := lineKey(line)
+            if isCommentLine(line) || !strings.HasPrefix(key, storedPrefix) {
+                return true
+            }
+            if match != nil && !match(readStoredLemma(key)) {
+                return true
+            }
+            lemma, entry, err := parsePosIndexLine(newFieldReader(indexFile.name, lineNumber, line))
+            if err == nil {
+                ret = append(ret, DataIndexPair { lemma, entry })
+            }
+            return true
+        }
+        if storedPrefix == "" {
+            indexFile.eachLine(addLine)
+        } else {
+            for _, line := range indexFile.linesWithPrefix(storedPrefix) {
+                addLine(line, 0)
+            }
+        }
+    }
+    return ret
+}
+
+// Converts a glob pattern to a regular expression matching whole lemmas.
+// Also returns the literal text the pattern starts with.
+func globToRegexp(pattern string) (*regexp.Regexp, string, error) {
+    expression := "^"
+    prefix := ""
+    literal := true
+    runes := []rune(pattern)
+    for i := 0; i < len(runes); i++ {
+        switch runes[i] {
+        case '*':
+            expression += ".*"
+            literal = false
+        case '?':
+            expression += "."
+            literal = false
+        case '[':
+            end := i + 1
+            for end < len(runes) && runes[end] != ']' {
+                end++
+            }
+            if end >= len(runes) {
+                return nil, "", fmt.Errorf("unterminated character class in %q", pattern)
+            }
+            class := string(runes[i + 1:end])
+            if strings.HasPrefix(class, "!") {
+                class = "^" + class[1:]
+            }
+            expression += "[" + strings.Replace(class, "\\", "\\\\", -1) + "]"
+            literal = false
+            i = end
+        default:
+            expression += regexp.QuoteMeta(string(runes[i]))
+            if literal {
+                prefix += string(runes[i])
+            }
+        }
+    }
+    re, err := regexp.Compile(expression + "$")
+    if err != nil {
+        return nil, "", fmt.Errorf("malformed pattern %q: %v", pattern, err)
+    }
+    return re, prefix, nil
+}
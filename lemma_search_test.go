@@ -0,0 +1,95 @@
+package gown
+
+import (
+    "reflect"
+    "regexp"
+    "strings"
+    "testing"
+)
+
+func TestLemmaSearch(t *testing.T) {
+    lemmas := func(pairs []DataIndexPair) []string {
+        ret := []string{}
+        for _, pair := range pairs {
+            ret = append(ret, pair.Lexeme)
+        }
+        return ret
+    }
+    contains := func(pairs []DataIndexPair, lemma string) bool {
+        for _, pair := range pairs {
+            if pair.Lexeme == lemma {
+                return true
+            }
+        }
+        return false
+    }
+
+    forEachLoadMode(t, LoadOptions{}, func(t *testing.T, wn *WN) {
+        computers := wn.PrefixSearch("Comput", LemmaSearchOptions{})
+        if !contains(computers, "computer") {
+            t.Errorf("expected \"comput\" to find \"computer\", got %v", lemmas(computers))
+        }
+        for i, pair := range computers {
+            if !strings.HasPrefix(pair.Lexeme, "comput") {
+                t.Errorf("unexpected match %q", pair.Lexeme)
+            }
+            if i > 0 && pair.Lexeme < computers[i - 1].Lexeme {
+                t.Errorf("expected the matches to be sorted")
+            }
+        }
+
+        all := wn.PrefixSearch("d", LemmaSearchOptions{})
+        page := wn.PrefixSearch("d", LemmaSearchOptions { Offset: 1, Limit: 2 })
+        if len(all) < 3 || !reflect.DeepEqual(page, all[1:3]) {
+            t.Errorf("expected the second page of 2 to be %v, got %v", lemmas(all[1:3]), lemmas(page))
+        }
+        for _, pair := range wn.PrefixSearch("d", LemmaSearchOptions { PartsOfSpeech: []int { POS_VERB } }) {
+            if pair.IndexEntry.PartOfSpeech != POS_VERB {
+                t.Errorf("expected only verbs, got %q %d", pair.Lexeme, pair.IndexEntry.PartOfSpeech)
+            }
+        }
+
+        globbed, err := wn.GlobSearch("comput*", LemmaSearchOptions{})
+        if err != nil || !reflect.DeepEqual(lemmas(globbed), lemmas(computers)) {
+            t.Errorf("expected \"comput*\" to match %v, got %v (%v)", lemmas(computers), lemmas(globbed), err)
+        }
+        for _, test := range []struct { pattern string; dog bool } {
+            { "?og", true },
+            { "[cd]og", true },
+            { "[!d]og", false },
+            { "*o*", true },
+            { "do", false },
+        } {
+            matches, err := wn.GlobSearch(test.pattern, LemmaSearchOptions{})
+            if err != nil || contains(matches, "dog") != test.dog {
+                t.Errorf("expected %q to match dog %v (%v)", test.pattern, test.dog, err)
+            }
+        }
+        if _, err := wn.GlobSearch("[do", LemmaSearchOptions{}); err == nil {
+            t.Errorf("expected an error for an unterminated character class")
+        }
+
+        matches := wn.RegexpSearch(regexp.MustCompile("^do.$"), LemmaSearchOptions { PartsOfSpeech: []int { POS_NOUN } })
+        if !contains(matches, "dog") {
+            t.Errorf("expected ^do.$ to match dog, got %v", lemmas(matches))
+        }
+    })
+
+    // the eager and lazy searches agree
+    eagerWn := loadTestWordNet(t, LoadOptions{})
+    lazyWn := loadTestWordNet(t, LoadOptions { Lazy: true })
+    pattern := regexp.MustCompile("og")
+    eager := eagerWn.RegexpSearch(pattern, LemmaSearchOptions{})
+    lazy := lazyWn.RegexpSearch(pattern, LemmaSearchOptions{})
+    if len(eager) == 0 || !reflect.DeepEqual(lemmas(eager), lemmas(lazy)) {
+        t.Errorf("expected the same matches, got %v and %v", lemmas(eager), lemmas(lazy))
+    }
+}
+
+func BenchmarkPrefixSearch(b *testing.B) {
+    wn, _ := loadSystemWordNet(b)
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        wn.PrefixSearch("comput", LemmaSearchOptions { Limit: 10 })
+    }
+}
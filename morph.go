@@ -1,12 +1,13 @@
 package gown
 
 import (
-    "bufio"
     "fmt"
     "io"
+    "io/fs"
     "os"
-    "path/filepath"
+    "sort"
     "strings"
+    "unicode"
 )
 
 var (
@@ -39,42 +40,68 @@ var (
     }
 )
 
-func (wn *WN) InitMorphData(dictDirname string) {
-    wn.exceptions = []map[string]string {
-        map[string]string{},    // noun
-        map[string]string{},    // verb
-        map[string]string{},    // adjective
-        map[string]string{},    // adverb
+// Loads the morphology exception lists (noun.exc, verb.exc, adj.exc and
+// adv.exc) used by Morph. Returns an error if any of them can't be read.
+func (wn *WN) InitMorphData(dictDirname string) error {
+    return wn.InitMorphDataFS(os.DirFS(dictDirname))
+}
+
+// Like InitMorphData, but reads the exception lists from the root of fsys.
+func (wn *WN) InitMorphDataFS(fsys fs.FS) error {
+    exceptions := []map[string][]string {
+        map[string][]string{},  // noun
+        map[string][]string{},  // verb
+        map[string][]string{},  // adjective
+        map[string][]string{},  // adverb
     }
 
     posNames := []string { "noun", "verb", "adj", "adv" }
     for posIndex, posName := range posNames {
-        exceptionFilename := dictDirname + string(filepath.Separator) + posName + ".exc"
-        infile, err := os.Open(exceptionFilename)
+        err := readExceptionFile(fsys, posName + ".exc", exceptions[posIndex])
         if err != nil {
-            panic(fmt.Sprintf("Can't open morph exception file %s: %v", exceptionFilename, err))
-        }
-        r := bufio.NewReader(infile)
-        if (r == nil) {
-            panic(fmt.Sprintf("Can't open morph exception file %s: %v" + exceptionFilename, err))
+            return err
         }
+    }
+    wn.setExceptions(exceptions)
+    return nil
+}
 
-        var readerr error = nil
-        for ; readerr == nil ; {
-            bytebuf, readerr := r.ReadBytes('\n')
-            if readerr != nil && readerr != io.EOF {
-                panic(readerr)
-            }
-            if len(bytebuf) == 0 {
-                break;
-            }
-            fields := strings.SplitN(strings.TrimSpace(string(bytebuf)), " ", -1)
-            derivedForm := strings.Replace(fields[0], "_", " ", -1)
-            baseForm := strings.Replace(fields[1], "_", " ", -1)
-            wn.exceptions[posIndex][derivedForm] = baseForm
+// Reads a POS.exc file. The format is:
+// inflected_form  base_form  [base_form...]
+// An inflected form may also be listed on several lines.
+func readExceptionFile(fsys fs.FS, exceptionFilename string, exceptions map[string][]string) error {
+    infile, err := openDictFile(fsys, exceptionFilename)
+    if err != nil {
+        return fmt.Errorf("can't open morph exception file %s: %v", exceptionFilename, err)
+    }
+    defer infile.Close()
+
+    lr := newLineReader(infile, exceptionFilename)
+    for {
+        line, err := lr.next()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return err
+        }
+        if len(strings.TrimSpace(line)) == 0 {
+            continue
+        }
+        f := lr.fields(line)
+        derivedForm := strings.Replace(f.str("inflected_form"), "_", " ", -1)
+        baseForms := []string { f.str("base_form") }
+        for f.more() {
+            baseForms = append(baseForms, f.str("base_form"))
+        }
+        if f.err != nil {
+            return f.err
+        }
+        for _, baseForm := range baseForms {
+            exceptions[derivedForm] = appendUnique(exceptions[derivedForm], strings.Replace(baseForm, "_", " ", -1))
         }
-        infile.Close()
     }
+    return nil
 }
 
 
@@ -82,7 +109,8 @@ func (wn *WN) InitMorphData(dictDirname string) {
 // raw form. (i.e. spaces are spaces, not '_') This algorithim is similar to,
 // but not exactly the same as the Wordnet Morphy algorithm.
 //
-// This function does not handle prepositional verb phrases,
+// This function does not handle prepositional verb phrases or collocations;
+// use MorphAll for those.
 // If no base morph is found, assumes the original word is the base.
 func (wn *WN) Morph(origword string, partOfSpeech int) string {
     partOfSpeechIndex := getPosIndex(partOfSpeech)
@@ -92,9 +120,9 @@ func (wn *WN) Morph(origword string, partOfSpeech int) string {
     }
 
     // check the exception lists
-    lemma, exists := wn.exceptions[partOfSpeechIndex][origword]
+    lemmas, exists := wn.exceptions[partOfSpeechIndex][origword]
     if exists {
-        return lemma
+        return lemmas[0]
     }
 
     if partOfSpeech == POS_ADVERB {
@@ -147,6 +175,203 @@ func (wn *WN) Morph(origword string, partOfSpeech int) string {
     return ""
 }
 
+// Returns all the base forms of the word defined for the part of speech,
+// following the WordNet morphstr() algorithm: all of the word's exceptions,
+// the word itself if it's defined, and every defined result of the suffix
+// rules. Collocations (e.g. "attorneys general", "mother-in-law") are
+// lemmatized word by word, and verb phrases with a preposition (e.g.
+// "looking for") by the verb. Returns an empty slice if there are none.
+func (wn *WN) MorphAll(origword string, partOfSpeech int) []string {
+    ret := []string{}
+    partOfSpeechIndex := getPosIndex(partOfSpeech)
+    if partOfSpeechIndex < 0 || partOfSpeechIndex > getPosIndex(POS_ADVERB) {
+        return ret
+    }
+    word := strings.ToLower(strings.Join(strings.Fields(strings.Replace(origword, "_", " ", -1)), " "))
+    if word == "" {
+        return ret
+    }
+
+    for _, lemma := range wn.morphWord(word, partOfSpeech) {
+        ret = appendUnique(ret, lemma)
+    }
+    if wn.isDefined(word, partOfSpeech) {
+        ret = appendUnique(ret, word)
+    }
+
+    words := strings.Split(word, " ")
+    if partOfSpeech == POS_VERB && len(words) > 1 && hasPreposition(words) {
+        for _, lemma := range wn.morphPrepositionalPhrase(words) {
+            ret = appendUnique(ret, lemma)
+        }
+        return ret
+    }
+
+    if strings.ContainsAny(word, " -") {
+        // lemmatize a collocation word by word
+        collocation := ""
+        start := 0
+        for i := 0; i <= len(word); i++ {
+            if i < len(word) && word[i] != ' ' && word[i] != '-' {
+                continue
+            }
+            part := word[start:i]
+            lemmas := wn.morphWord(part, partOfSpeech)
+            if len(lemmas) > 0 {
+                part = lemmas[0]
+            }
+            collocation += part
+            if i < len(word) {
+                collocation += word[i:i + 1]
+            }
+            start = i + 1
+        }
+        if collocation != word && wn.isDefined(collocation, partOfSpeech) {
+            ret = appendUnique(ret, collocation)
+        }
+    }
+    return ret
+}
+
+type MorphResult struct {
+    Lemma string
+    PartOfSpeech int       // POS_NOUN, POS_VERB, POS_ADJECTIVE or POS_ADVERB
+    TagSenseCount int      // number of senses of the lemma tagged in the semantic concordances
+}
+
+// Returns every base form of the word in every part of speech (see
+// MorphAll), most frequently tagged first.
+func (wn *WN) MorphAnyPartOfSpeech(origword string) []MorphResult {
+    ret := []MorphResult{}
+    for _, pos := range []int { POS_NOUN, POS_VERB, POS_ADJECTIVE, POS_ADVERB } {
+        for _, lemma := range wn.MorphAll(origword, pos) {
+            dataIndexEntry := wn.LookupWithPartOfSpeech(lemma, pos)
+            if dataIndexEntry == nil {
+                // -ful forms aren't necessarily defined
+                continue
+            }
+            ret = append(ret, MorphResult { lemma, pos, dataIndexEntry.TagSenseCount })
+        }
+    }
+    sort.SliceStable(ret, func(i, j int) bool {
+        return ret[i].TagSenseCount > ret[j].TagSenseCount
+    })
+    return ret
+}
+
+// Returns the base forms of a single word from the exception list and the
+// suffix rules, like morphword() in the WordNet library. The word itself is
+// not included.
+func (wn *WN) morphWord(word string, partOfSpeech int) []string {
+    partOfSpeechIndex := getPosIndex(partOfSpeech)
+    ret := []string{}
+    if wn.exceptions != nil {
+        for _, lemma := range wn.exceptions[partOfSpeechIndex][word] {
+            ret = appendUnique(ret, lemma)
+        }
+    }
+    if partOfSpeech == POS_ADVERB {
+        // only use the exception lists for adverbs
+        return ret
+    }
+
+    stem := word
+    ending := ""
+    if partOfSpeech == POS_NOUN {
+        if strings.HasSuffix(word, "ful") {
+            // lemmatize the noun before the -ful (e.g. "boxesful" -> "boxful")
+            stem = word[:len(word) - 3]
+            ending = "ful"
+        } else if strings.HasSuffix(word, "ss") || len(word) <= 2 {
+            return ret
+        }
+    }
+
+    for i := 1; i <= 4; i++ {
+        suffixIndex := len(stem) - i
+        if suffixIndex <= 0 {
+            break
+        }
+        replacements, found := suffixReplacements[partOfSpeechIndex][stem[suffixIndex:]]
+        if !found {
+            continue
+        }
+        for _, replacement := range replacements {
+            possibleLemma := stem[:suffixIndex] + replacement
+            if possibleLemma != stem && wn.isDefined(possibleLemma, partOfSpeech) {
+                ret = appendUnique(ret, possibleLemma + ending)
+            }
+        }
+    }
+    return ret
+}
+
+// prepositions that may follow a verb in a phrasal verb (e.g. "look for")
+var morphPrepositions = []string {
+    "to", "at", "of", "on", "off", "in", "out", "up", "down", "from", "with", "into", "for", "about", "between",
+}
+
+func hasPreposition(words []string) bool {
+    for _, word := range words[1:] {
+        for _, preposition := range morphPrepositions {
+            if word == preposition {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// Lemmatizes a verb phrase containing a preposition by the verb, which is
+// assumed to be the first word, like morphprep() in the WordNet library. If
+// the phrase has more than two words, the last one is also tried as a noun.
+// (e.g. "pulling one's legs" -> "pull one's leg")
+func (wn *WN) morphPrepositionalPhrase(words []string) []string {
+    ret := []string{}
+    verb := words[0]
+    for _, c := range verb {
+        if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+            return ret
+        }
+    }
+
+    rests := []string { strings.Join(words[1:], " ") }
+    if len(words) > 2 {
+        for _, lastWord := range wn.morphWord(words[len(words) - 1], POS_NOUN) {
+            rests = append(rests, strings.Join(words[1:len(words) - 1], " ") + " " + lastWord)
+        }
+    }
+
+    verbs := []string{}
+    if wn.exceptions != nil {
+        verbs = append(verbs, wn.exceptions[getPosIndex(POS_VERB)][verb]...)
+    }
+    for i := 1; i <= 4; i++ {
+        suffixIndex := len(verb) - i
+        if suffixIndex <= 0 {
+            break
+        }
+        for _, replacement := range suffixReplacements[getPosIndex(POS_VERB)][verb[suffixIndex:]] {
+            verbs = append(verbs, verb[:suffixIndex] + replacement)
+        }
+    }
+    verbs = append(verbs, verb)
+
+    for _, baseVerb := range verbs {
+        for _, rest := range rests {
+            phrase := baseVerb + " " + rest
+            if wn.isDefined(phrase, POS_VERB) {
+                ret = appendUnique(ret, phrase)
+            }
+        }
+    }
+    return ret
+}
+
+func (wn *WN) isDefined(lemma string, partOfSpeech int) bool {
+    return wn.LookupWithPartOfSpeech(lemma, partOfSpeech) != nil
+}
+
 func getPosIndex(pos int) int {
     if pos == POS_ADJECTIVE_SATELLITE {
         pos = POS_ADJECTIVE
@@ -0,0 +1,193 @@
+package gown
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "io"
+    "strconv"
+    "strings"
+)
+
+var (
+    ErrMissingField = errors.New("missing field")
+    ErrUnknownPointerSymbol = errors.New("unknown pointer symbol")
+    ErrMalformedField = errors.New("malformed field")
+    ErrDuplicateLemma = errors.New("duplicate lemma")
+)
+
+// Describes a problem reading one of the WordNet database files.
+type ParseError struct {
+    Filename string // the file being read
+    Line int        // 1 based line number, or 0 if the error isn't specific to a line
+    Field string    // name of the offending field from wndb(5WN) (e.g. "synset_offset"), if any
+    Value string    // text of the offending field, if any
+    Err error       // the underlying error
+}
+
+func (e *ParseError) Error() string {
+    location := e.Filename
+    if e.Line > 0 {
+        location = fmt.Sprintf("%s:%d", e.Filename, e.Line)
+    }
+    if e.Field == "" {
+        return fmt.Sprintf("%s: %v", location, e.Err)
+    }
+    return fmt.Sprintf("%s: %s %q: %v", location, e.Field, e.Value, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+    return e.Err
+}
+
+// Reads a WordNet file line by line, keeping track of the line number for
+// error reporting.
+type lineReader struct {
+    r *bufio.Reader
+    filename string
+    lineNumber int
+}
+
+func newLineReader(r io.Reader, filename string) *lineReader {
+    return &lineReader {
+        r: bufio.NewReader(r),
+        filename: filename,
+    }
+}
+
+// Returns the next line without its line terminator. Returns io.EOF when
+// there are no more lines, or a *ParseError if the read failed.
+func (lr *lineReader) next() (string, error) {
+    bytebuf, readerr := lr.r.ReadBytes('\n')
+    if readerr != nil && readerr != io.EOF {
+        return "", lr.errorf(readerr)
+    }
+    if len(bytebuf) == 0 {
+        return "", io.EOF
+    }
+    lr.lineNumber++
+    return strings.TrimRight(string(bytebuf), "\r\n"), nil
+}
+
+func (lr *lineReader) errorf(err error) *ParseError {
+    return &ParseError {
+        Filename: lr.filename,
+        Line: lr.lineNumber,
+        Err: err,
+    }
+}
+
+// Returns a *ParseError for a malformed field of the current line.
+func (lr *lineReader) fieldError(name string, value string) *ParseError {
+    return newFieldError(lr.filename, lr.lineNumber, name, value)
+}
+
+// Returns a fieldReader over the whitespace separated fields of the current
+// line.
+func (lr *lineReader) fields(line string) *fieldReader {
+    return newFieldReader(lr.filename, lr.lineNumber, line)
+}
+
+func newFieldError(filename string, lineNumber int, name string, value string) *ParseError {
+    return &ParseError {
+        Filename: filename,
+        Line: lineNumber,
+        Field: name,
+        Value: value,
+        Err: ErrMalformedField,
+    }
+}
+
+// Consumes the fields of a line in order. The first error is kept and
+// every later read returns a zero value, so a whole line can be parsed
+// before checking err.
+type fieldReader struct {
+    filename string
+    lineNumber int
+    fields []string
+    index int
+    err *ParseError
+}
+
+// Returns a fieldReader over the whitespace separated fields of a line.
+// lineNumber is only used for error reporting, and may be 0 if unknown.
+func newFieldReader(filename string, lineNumber int, line string) *fieldReader {
+    return &fieldReader {
+        filename: filename,
+        lineNumber: lineNumber,
+        fields: strings.Fields(line),
+    }
+}
+
+func (f *fieldReader) fail(name string, value string, err error) {
+    if f.err == nil {
+        f.err = newFieldError(f.filename, f.lineNumber, name, value)
+        f.err.Err = err
+    }
+}
+
+// true if there are unread fields
+func (f *fieldReader) more() bool {
+    return f.err == nil && f.index < len(f.fields)
+}
+
+func (f *fieldReader) str(name string) string {
+    if f.err != nil {
+        return ""
+    }
+    if f.index >= len(f.fields) {
+        f.fail(name, "", ErrMissingField)
+        return ""
+    }
+    value := f.fields[f.index]
+    f.index++
+    return value
+}
+
+func (f *fieldReader) decimal(name string) int {
+    return f.integer(name, 10)
+}
+
+func (f *fieldReader) hex(name string) int {
+    return f.integer(name, 16)
+}
+
+func (f *fieldReader) integer(name string, base int) int {
+    value := f.str(name)
+    if f.err != nil {
+        return 0
+    }
+    n, err := strconv.ParseInt(value, base, 0)
+    if err != nil {
+        f.fail(name, value, ErrMalformedField)
+        return 0
+    }
+    return int(n)
+}
+
+func (f *fieldReader) float(name string) float64 {
+    value := f.str(name)
+    if f.err != nil {
+        return 0
+    }
+    n, err := strconv.ParseFloat(value, 64)
+    if err != nil {
+        f.fail(name, value, ErrMalformedField)
+        return 0
+    }
+    return n
+}
+
+// Reads a pointer symbol and maps it to its relationship type.
+func (f *fieldReader) pointerSymbol(name string) int {
+    value := f.str(name)
+    if f.err != nil {
+        return 0
+    }
+    relationshipType, symbolFound := RELATIONSHIP_POINTER_SYMBOLS[value]
+    if !symbolFound {
+        f.fail(name, value, ErrUnknownPointerSymbol)
+        return 0
+    }
+    return relationshipType
+}
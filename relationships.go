@@ -0,0 +1,180 @@
+package gown
+
+/*
+Many pointers in the database only go one way. Entailments, causes,
+participles and pertainyms have no inverse pointer symbol at all, and a
+hypernym may be missing its hyponym pointer back. Verbs point to their
+troponyms with the hyponym symbol "~", so the inverse of a verb hypernym is
+only added as a TROPONYM_RELATIONSHIP where that's missing, and the "~" edges
+are left as HYPONYM_RELATIONSHIPs. The troponyms of a verb are the targets of
+both.
+AddInverseRelationships inserts the missing edges so the graph can be
+traversed in either direction.
+*/
+
+// identifies an edge for detecting duplicates
+type edgeKey struct {
+    from synsetKey
+    relationshipType int
+    to synsetKey
+    sourceWordNumber int
+    targetWordNumber int
+}
+
+// Adds the inverse of every relationship that doesn't already have one
+// (see RELATIONSHIP_INVERSES), so that for example "die" gets a
+// CAUSED_BY_RELATIONSHIP to "kill". A verb hypernym gets a
+// TROPONYM_RELATIONSHIP back unless it already has a HYPONYM_RELATIONSHIP
+// ("~") back, which isn't retyped, so follow both types to find the
+// troponyms of a verb. Calling it again does nothing.
+func (wn *WN) AddInverseRelationships() {
+    wn.addRelationships(inverseRelationshipEdges(wn.Iter()))
+}
+
+// Appends edges to the Relationships of the synsets they're indexed by, and
+// drops everything computed from the old edges.
+func (wn *WN) addRelationships(edges map[synsetKey][]RelationshipEdge) {
+    if wn.lazy != nil {
+        wn.lazy.appendOverlay(edges)
+    } else {
+        wn.dataLock.Lock()
+        // the changed data files are copied, so Iter can keep reading the
+        // old ones
+        posData := map[int]*dataFile{}
+        for pos, dataFile := range wn.posData {
+            posData[pos] = dataFile
+        }
+        copied := map[int]bool{}
+        for k, synsetEdges := range edges {
+            synsets, exists := posData[k.pos]
+            if !exists {
+                continue
+            }
+            synset, exists := (*synsets)[k.offset]
+            if !exists {
+                continue
+            }
+            if !copied[k.pos] {
+                synsetsCopy := make(dataFile, len(*synsets))
+                for offset, s := range *synsets {
+                    synsetsCopy[offset] = s
+                }
+                synsets = &synsetsCopy
+                posData[k.pos] = synsets
+                copied[k.pos] = true
+            }
+            relationships := make([]RelationshipEdge, 0, len(synset.Relationships) + len(synsetEdges))
+            relationships = append(relationships, synset.Relationships...)
+            synset.Relationships = append(relationships, synsetEdges...)
+            (*synsets)[k.offset] = synset
+        }
+        wn.posData = posData
+        wn.senseIndex = linkSenses(wn.senseIndex, posData)
+        wn.dataLock.Unlock()
+    }
+
+    wn.dropHypernymIndex()
+    wn.taxonomyDepthsLock.Lock()
+    wn.taxonomyDepths = nil
+    wn.taxonomyDepthsLock.Unlock()
+    wn.verbGroupsLock.Lock()
+    wn.verbGroups = nil
+    wn.verbGroupsLock.Unlock()
+}
+
+// Returns the edges that are missing an inverse, indexed by the synset they
+// should be added to.
+func inverseRelationshipEdges(synsets <-chan *Synset) map[synsetKey][]RelationshipEdge {
+    type sourcedEdge struct {
+        from *Synset
+        edge RelationshipEdge
+    }
+    existing := map[edgeKey]bool{}
+    edges := []sourcedEdge{}
+    for synset := range synsets {
+        from := getSynsetKey(synset)
+        for _, edge := range synset.Relationships {
+            existing[getEdgeKey(from, edge)] = true
+            edges = append(edges, sourcedEdge { synset, edge })
+        }
+    }
+
+    inverses := map[synsetKey][]RelationshipEdge{}
+    for _, e := range edges {
+        inverse, exists := invertEdge(e.from, e.edge)
+        if !exists {
+            continue
+        }
+        to := synsetKey { normalizePos(e.edge.PartOfSpeech), e.edge.SynsetOffset }
+        k := getEdgeKey(to, inverse)
+        if existing[k] {
+            continue
+        }
+        existing[k] = true
+        inverses[to] = append(inverses[to], inverse)
+    }
+    return inverses
+}
+
+// Returns the edge pointing back from the target of an edge of the synset.
+// The second return value is false if the relationship has no inverse.
+func invertEdge(from *Synset, edge RelationshipEdge) (RelationshipEdge, bool) {
+    inverseType, exists := RELATIONSHIP_INVERSES[edge.RelationshipType]
+    if !exists {
+        return RelationshipEdge{}, false
+    }
+    if edge.RelationshipType == HYPERNYM_RELATIONSHIP && from.PartOfSpeech == POS_VERB {
+        inverseType = TROPONYM_RELATIONSHIP
+    }
+    return RelationshipEdge {
+        RelationshipType: inverseType,
+        SynsetOffset: from.SynsetOffset,
+        PartOfSpeech: from.PartOfSpeech,
+        SourceWordNumber: edge.TargetWordNumber,
+        TargetWordNumber: edge.SourceWordNumber,
+    }, true
+}
+
+func getEdgeKey(from synsetKey, edge RelationshipEdge) edgeKey {
+    relationshipType := edge.RelationshipType
+    // verbs point to their troponyms with "~", so a troponym is a hyponym
+    if relationshipType == TROPONYM_RELATIONSHIP {
+        relationshipType = HYPONYM_RELATIONSHIP
+    }
+    return edgeKey {
+        from,
+        relationshipType,
+        synsetKey { normalizePos(edge.PartOfSpeech), edge.SynsetOffset },
+        edge.SourceWordNumber,
+        edge.TargetWordNumber,
+    }
+}
+
+// adjective satellites are stored with the adjectives
+func normalizePos(pos int) int {
+    if pos == POS_ADJECTIVE_SATELLITE {
+        return POS_ADJECTIVE
+    }
+    return pos
+}
+
+// Returns a copy of the sense index pointing every entry at its synset in
+// posData. The entries hold copies of the synsets, so this is needed after
+// changing them.
+func linkSenses(index senseIndex, posData map[int]*dataFile) senseIndex {
+    ret := make(senseIndex, len(index))
+    for lemma, senses := range index {
+        linked := make([]SenseIndexEntry, len(senses))
+        for i, sense := range senses {
+            linked[i] = sense
+            linked[i].synsetPtr = nil
+            if dataFile, exists := posData[normalizePos(sense.PartOfSpeech)]; exists {
+                if synset, exists := (*dataFile)[sense.SynsetOffset]; exists {
+                    linked[i].synsetPtr = &synset
+                }
+            }
+        }
+        ret[lemma] = linked
+    }
+    return ret
+}
@@ -0,0 +1,114 @@
+package gown
+
+import (
+    "reflect"
+    "testing"
+)
+
+func countRelationships(synset *Synset, relationshipType int, target *Synset) int {
+    count := 0
+    for _, edge := range synset.Relationships {
+        if edge.RelationshipType == relationshipType && edge.SynsetOffset == target.SynsetOffset &&
+            getPosIndex(edge.PartOfSpeech) == getPosIndex(target.PartOfSpeech) {
+            count++
+        }
+    }
+    return count
+}
+
+func TestAddInverseRelationships(t *testing.T) {
+    forEachLoadMode(t, LoadOptions { InverseRelationships: true }, func(t *testing.T, wn *WN) {
+        // calling it again must not add duplicates
+        wn.AddInverseRelationships()
+
+        tests := []struct {
+            from string
+            relationshipType int
+            to string
+        } {
+            { "die", CAUSED_BY_RELATIONSHIP, "kill" },
+            { "sleep", ENTAILED_BY_RELATIONSHIP, "snore" },
+        }
+        for _, test := range tests {
+            from := wn.LookupWithPartOfSpeechAndSense(test.from, POS_VERB, 1)
+            to := wn.LookupWithPartOfSpeechAndSense(test.to, POS_VERB, 1)
+            if from == nil || to == nil {
+                t.Fatalf("%q or %q not found", test.from, test.to)
+            }
+            count := countRelationships(from.GetSynsetPtr(), test.relationshipType, to.GetSynsetPtr())
+            if count != 1 {
+                t.Errorf("expected one %s from %q to %q but found %d",
+                    RELATIONSHIP_ID_TO_STRING[test.relationshipType], test.from, test.to, count)
+            }
+        }
+
+        // "travel" already points to "walk" with "~", so it gets no troponym
+        travel := wn.LookupWithPartOfSpeechAndSense("travel", POS_VERB, 1).GetSynsetPtr()
+        walk := wn.LookupWithPartOfSpeechAndSense("walk", POS_VERB, 1).GetSynsetPtr()
+        if countRelationships(travel, HYPONYM_RELATIONSHIP, walk) != 1 || countRelationships(travel, TROPONYM_RELATIONSHIP, walk) != 0 {
+            t.Errorf("expected a single hyponym from \"travel\" to \"walk\": %v", travel.Relationships)
+        }
+        // its troponyms are all "~" edges, which are found through HYPONYM_RELATIONSHIPS
+        troponyms := wn.Related(travel, HYPONYM_RELATIONSHIPS)
+        if len(troponyms) != 4 || troponyms[1].SynsetOffset != walk.SynsetOffset {
+            t.Errorf("expected the 4 troponyms of \"travel\" including \"walk\", but got %v", troponyms)
+        }
+    })
+}
+
+func TestInverseVerbHypernyms(t *testing.T) {
+    synsets := func(synsets ...*Synset) <-chan *Synset {
+        out := make(chan *Synset, len(synsets))
+        for _, synset := range synsets {
+            out <- synset
+        }
+        close(out)
+        return out
+    }
+    travel := &Synset { SynsetOffset: 1, PartOfSpeech: POS_VERB }
+    walk := &Synset { SynsetOffset: 2, PartOfSpeech: POS_VERB, Relationships: []RelationshipEdge {
+        { HYPERNYM_RELATIONSHIP, 1, POS_VERB, 0, 0 },
+    } }
+
+    inverses := inverseRelationshipEdges(synsets(travel, walk))
+    expected := []RelationshipEdge { { TROPONYM_RELATIONSHIP, 2, POS_VERB, 0, 0 } }
+    if !reflect.DeepEqual(inverses[getSynsetKey(travel)], expected) {
+        t.Errorf("expected a troponym from travel to walk, got %v", inverses)
+    }
+
+    travel.Relationships = []RelationshipEdge { { HYPONYM_RELATIONSHIP, 2, POS_VERB, 0, 0 } }
+    inverses = inverseRelationshipEdges(synsets(travel, walk))
+    if len(inverses) != 0 {
+        t.Errorf("expected no inverses of a verb hypernym with a hyponym back, got %v", inverses)
+    }
+}
+
+func TestAddRelationshipsClearsCaches(t *testing.T) {
+    forEachLoadMode(t, LoadOptions{}, func(t *testing.T, wn *WN) {
+        wn.taxonomyDepth(POS_NOUN)
+        wn.getVerbGroups()
+        wn.BuildHypernymIndex()
+        wn.AddInverseRelationships()
+        if wn.taxonomyDepths != nil || wn.verbGroups != nil || wn.hypernymIndex != nil {
+            t.Errorf("expected AddInverseRelationships to clear the cached depths, verb groups and hypernym index")
+        }
+    })
+}
+
+func TestAddInverseRelationshipsWhileReading(t *testing.T) {
+    forEachLoadMode(t, LoadOptions{}, func(t *testing.T, wn *WN) {
+        done := make(chan bool)
+        go func() {
+            for i := 0; i < 20; i++ {
+                for synset := range wn.Iter() {
+                    wn.GetSynset(synset.PartOfSpeech, synset.SynsetOffset)
+                }
+                wn.Lookup("dog")
+            }
+            close(done)
+        }()
+        wn.AddInverseRelationships()
+        wn.ConnectVerbGroups()
+        <-done
+    })
+}
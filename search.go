@@ -0,0 +1,336 @@
+package gown
+
+import (
+    "fmt"
+    "math"
+    "sort"
+    "strings"
+    "unicode"
+)
+
+/*
+The search index is an inverted index over the words and gloss of every
+synset, recording the positions of each term so phrases can be matched.
+Queries are made of terms and "quoted phrases", all of which must match,
+and alternatives separated by OR. AND may be written between terms, but
+does nothing since it's the default:
+
+    enzyme "amino acid" OR protein
+    enzyme AND "amino acid" OR protein
+
+finds the synsets mentioning both enzyme and the phrase "amino acid", and
+those mentioning protein. Results are ranked by BM25 over all the terms of
+the query.
+*/
+
+const SEARCH_BM25_K1 float64 = 1.2
+const SEARCH_BM25_B float64 = 0.75
+
+type SearchQuery struct {
+    Query string
+    PartsOfSpeech []int         // only synsets with one of these parts of speech, or any if empty
+    LexographerFilenums []int   // only synsets in one of these lexographer files, or any if empty
+    Limit int                   // the maximum number of results, or no limit if 0
+}
+
+type SearchResult struct {
+    Synset *Synset
+    Score float64
+}
+
+type searchIndex struct {
+    lemmatize bool
+    docs []searchDoc
+    postings map[string][]searchPosting // sorted by doc
+    averageLength float64
+}
+
+type searchDoc struct {
+    key synsetKey
+    lexographerFilenum int
+    length int
+}
+
+type searchPosting struct {
+    doc int32
+    positions []int32
+}
+
+// Builds the index used by Search. With lemmatize, words are indexed and
+// searched by their base forms (e.g. "barked" finds "bark"). That needs
+// InitMorphData first, or words are indexed as they are. Rebuild it after
+// changing the database.
+func (wn *WN) BuildSearchIndex(lemmatize bool) {
+    index := &searchIndex {
+        lemmatize: lemmatize,
+        docs: []searchDoc{},
+        postings: map[string][]searchPosting{},
+    }
+    // documents are numbered in (pos, offset) order, so ties rank the same
+    // way every time
+    synsets := []*Synset{}
+    for synset := range wn.Iter() {
+        synsets = append(synsets, synset)
+    }
+    sort.Slice(synsets, func(i, j int) bool { return getSynsetKey(synsets[i]).less(getSynsetKey(synsets[j])) })
+
+    normalized := map[string]string{}
+    totalLength := 0
+    for _, synset := range synsets {
+        doc := int32(len(index.docs))
+        positions := map[string][]int32{}
+        position := int32(0)
+        add := func(text string) {
+            for _, token := range searchTokens(text) {
+                term, seen := normalized[token]
+                if !seen {
+                    term = wn.searchTerm(token, lemmatize)
+                    normalized[token] = term
+                }
+                positions[term] = append(positions[term], position)
+                position++
+            }
+            // leave a gap so a phrase can't span two words of the synset
+            position++
+        }
+        for _, word := range synset.Words {
+            add(word)
+        }
+        add(synset.Gloss)
+
+        for term, termPositions := range positions {
+            index.postings[term] = append(index.postings[term], searchPosting { doc, termPositions })
+        }
+        length := int(position) - len(synset.Words) - 1
+        index.docs = append(index.docs, searchDoc { getSynsetKey(synset), synset.LexographerFilenum, length })
+        totalLength += length
+    }
+    if len(index.docs) > 0 {
+        index.averageLength = float64(totalLength) / float64(len(index.docs))
+    }
+
+    wn.searchIndexLock.Lock()
+    wn.searchIndex = index
+    wn.searchIndexLock.Unlock()
+}
+
+// Returns the synsets matching the query, best first. Returns an error if
+// BuildSearchIndex wasn't called or the query has no terms.
+func (wn *WN) Search(query SearchQuery) ([]SearchResult, error) {
+    wn.searchIndexLock.RLock()
+    index := wn.searchIndex
+    wn.searchIndexLock.RUnlock()
+    if index == nil {
+        return nil, fmt.Errorf("no search index, call BuildSearchIndex first")
+    }
+
+    clauses := wn.parseSearchQuery(query.Query, index.lemmatize)
+    if len(clauses) == 0 {
+        return nil, fmt.Errorf("no terms in search query %q", query.Query)
+    }
+
+    // the term frequencies of every term and phrase of the query
+    frequencies := map[string]map[int32]int{}
+    for _, clause := range clauses {
+        for _, phrase := range clause {
+            k := strings.Join(phrase, " ")
+            if _, exists := frequencies[k]; !exists {
+                frequencies[k] = index.phraseFrequencies(phrase)
+            }
+        }
+    }
+
+    matches := map[int32]bool{}
+    for _, clause := range clauses {
+        // start from the rarest term
+        phrases := make([]string, len(clause))
+        for i, phrase := range clause {
+            phrases[i] = strings.Join(phrase, " ")
+        }
+        sort.Slice(phrases, func(i, j int) bool {
+            if len(frequencies[phrases[i]]) != len(frequencies[phrases[j]]) {
+                return len(frequencies[phrases[i]]) < len(frequencies[phrases[j]])
+            }
+            return phrases[i] < phrases[j]
+        })
+        for doc, _ := range frequencies[phrases[0]] {
+            all := true
+            for _, phrase := range phrases[1:] {
+                if _, exists := frequencies[phrase][doc]; !exists {
+                    all = false
+                    break
+                }
+            }
+            if all && index.docs[doc].matches(query) {
+                matches[doc] = true
+            }
+        }
+    }
+
+    scores := make([]struct { doc int32; score float64 }, 0, len(matches))
+    n := float64(len(index.docs))
+    for doc, _ := range matches {
+        score := 0.0
+        lengthNorm := 1 - SEARCH_BM25_B + SEARCH_BM25_B * float64(index.docs[doc].length) / index.averageLength
+        for _, docFrequencies := range frequencies {
+            tf, exists := docFrequencies[doc]
+            if !exists {
+                continue
+            }
+            df := float64(len(docFrequencies))
+            idf := math.Log(1 + (n - df + 0.5) / (df + 0.5))
+            score += idf * float64(tf) * (SEARCH_BM25_K1 + 1) / (float64(tf) + SEARCH_BM25_K1 * lengthNorm)
+        }
+        scores = append(scores, struct { doc int32; score float64 } { doc, score })
+    }
+    sort.Slice(scores, func(i, j int) bool {
+        if scores[i].score != scores[j].score {
+            return scores[i].score > scores[j].score
+        }
+        return scores[i].doc < scores[j].doc
+    })
+
+    ret := []SearchResult{}
+    for _, scored := range scores {
+        if query.Limit > 0 && len(ret) >= query.Limit {
+            break
+        }
+        k := index.docs[scored.doc].key
+        synset := wn.GetSynset(k.pos, k.offset)
+        if synset != nil {
+            ret = append(ret, SearchResult { synset, scored.score })
+        }
+    }
+    return ret, nil
+}
+
+func (d searchDoc) matches(query SearchQuery) bool {
+    if len(query.PartsOfSpeech) > 0 {
+        found := false
+        for _, pos := range query.PartsOfSpeech {
+            if normalizePos(pos) == d.key.pos {
+                found = true
+            }
+        }
+        if !found {
+            return false
+        }
+    }
+    if len(query.LexographerFilenums) > 0 && !containsInt(query.LexographerFilenums, d.lexographerFilenum) {
+        return false
+    }
+    return true
+}
+
+// Returns the number of times the phrase occurs in each document it occurs
+// in.
+func (index *searchIndex) phraseFrequencies(phrase []string) map[int32]int {
+    ret := map[int32]int{}
+    first := index.postings[phrase[0]]
+    for _, posting := range first {
+        count := 0
+        for _, start := range posting.positions {
+            found := true
+            for i := 1; i < len(phrase) && found; i++ {
+                found = index.hasPosition(phrase[i], posting.doc, start + int32(i))
+            }
+            if found {
+                count++
+            }
+        }
+        if count > 0 {
+            ret[posting.doc] = count
+        }
+    }
+    return ret
+}
+
+func (index *searchIndex) hasPosition(term string, doc int32, position int32) bool {
+    postings := index.postings[term]
+    i := sort.Search(len(postings), func(i int) bool { return postings[i].doc >= doc })
+    if i >= len(postings) || postings[i].doc != doc {
+        return false
+    }
+    for _, p := range postings[i].positions {
+        if p == position {
+            return true
+        }
+    }
+    return false
+}
+
+// Parses a query into alternatives, each a list of phrases that must all
+// match. A single term is a phrase of one word.
+func (wn *WN) parseSearchQuery(query string, lemmatize bool) [][][]string {
+    clauses := [][][]string{}
+    clause := [][]string{}
+    endClause := func() {
+        if len(clause) > 0 {
+            clauses = append(clauses, clause)
+        }
+        clause = [][]string{}
+    }
+    addPhrase := func(text string) {
+        phrase := []string{}
+        for _, token := range searchTokens(text) {
+            phrase = append(phrase, wn.searchTerm(token, lemmatize))
+        }
+        if len(phrase) > 0 {
+            clause = append(clause, phrase)
+        }
+    }
+
+    for query != "" {
+        query = strings.TrimLeftFunc(query, unicode.IsSpace)
+        if strings.HasPrefix(query, "\"") {
+            end := strings.Index(query[1:], "\"")
+            if end < 0 {
+                // an unterminated phrase runs to the end
+                end = len(query) - 1
+            }
+            addPhrase(query[1:end + 1])
+            if end + 2 < len(query) {
+                query = query[end + 2:]
+            } else {
+                query = ""
+            }
+            continue
+        }
+        end := strings.IndexFunc(query, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+        if end < 0 {
+            end = len(query)
+        }
+        if query[:end] == "OR" {
+            endClause()
+        } else if query[:end] == "AND" {
+            // terms must all match anyway
+        } else {
+            addPhrase(query[:end])
+        }
+        query = query[end:]
+    }
+    endClause()
+    return clauses
+}
+
+// Splits text into lower case words.
+func searchTokens(text string) []string {
+    return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+        return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+    })
+}
+
+// Returns the indexed form of a token: its base form if lemmatize is set
+// and one is found.
+func (wn *WN) searchTerm(token string, lemmatize bool) string {
+    if !lemmatize || len(wn.exceptions) == 0 {
+        return token
+    }
+    for _, pos := range []int { POS_NOUN, POS_VERB, POS_ADJECTIVE, POS_ADVERB } {
+        lemma := wn.Morph(token, pos)
+        if lemma != "" {
+            return lemma
+        }
+    }
+    return token
+}
@@ -0,0 +1,110 @@
+package gown
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestSearch(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+    cat := wn.LookupWithPartOfSpeechAndSense("cat", POS_NOUN, 1).GetSynsetPtr()
+
+    if _, err := wn.Search(SearchQuery { Query: "dog" }); err == nil {
+        t.Errorf("expected an error searching without an index")
+    }
+
+    found := func(results []SearchResult, synset *Synset) bool {
+        for _, result := range results {
+            if getSynsetKey(result.Synset) == getSynsetKey(synset) {
+                return true
+            }
+        }
+        return false
+    }
+    tests := []struct {
+        query SearchQuery
+        lemmatize bool
+        dog bool
+        cat bool
+    } {
+        { SearchQuery { Query: "barked" }, false, true, false },
+        { SearchQuery { Query: "bark" }, false, false, false },
+        { SearchQuery { Query: "bark" }, true, true, false },
+        { SearchQuery { Query: "\"dog barked\"" }, false, true, false },
+        { SearchQuery { Query: "\"barked dog\"" }, false, false, false },
+        { SearchQuery { Query: "Canis barked" }, false, true, false },
+        { SearchQuery { Query: "canis feline" }, false, false, false },
+        { SearchQuery { Query: "canis AND barked" }, false, true, false },
+        { SearchQuery { Query: "canis AND feline" }, false, false, false },
+        { SearchQuery { Query: "canis AND barked OR feline" }, false, true, true },
+        { SearchQuery { Query: "canis OR feline" }, false, true, true },
+        { SearchQuery { Query: "\"domestic dog\"" }, false, true, false },
+        { SearchQuery { Query: "barked", PartsOfSpeech: []int { POS_VERB } }, false, false, false },
+        { SearchQuery { Query: "barked", PartsOfSpeech: []int { POS_VERB, POS_NOUN } }, false, true, false },
+        { SearchQuery { Query: "barked", LexographerFilenums: []int { dog.LexographerFilenum } }, false, true, false },
+        { SearchQuery { Query: "barked", LexographerFilenums: []int { dog.LexographerFilenum + 1 } }, false, false, false },
+    }
+    for _, lemmatize := range []bool { false, true } {
+        wn.BuildSearchIndex(lemmatize)
+        for _, test := range tests {
+            if test.lemmatize != lemmatize {
+                continue
+            }
+            results, err := wn.Search(test.query)
+            if err != nil {
+                t.Errorf("can't search for %v: %v", test.query, err)
+                continue
+            }
+            if found(results, dog) != test.dog || found(results, cat) != test.cat {
+                t.Errorf("expected a search for %v to find dog %v and cat %v", test.query, test.dog, test.cat)
+            }
+            for i := 1; i < len(results); i++ {
+                if results[i].Score > results[i - 1].Score {
+                    t.Errorf("expected the results for %v to be ranked", test.query)
+                }
+            }
+        }
+    }
+
+    results, _ := wn.Search(SearchQuery { Query: "canis OR feline", Limit: 1 })
+    if len(results) != 1 {
+        t.Errorf("expected 1 result, got %d", len(results))
+    }
+    if _, err := wn.Search(SearchQuery { Query: " OR \"\" " }); err == nil {
+        t.Errorf("expected an error for a query without terms")
+    }
+}
+
+func TestSearchTies(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    var first []SearchResult = nil
+    for i := 0; i < 5; i++ {
+        wn.BuildSearchIndex(false)
+        results, err := wn.Search(SearchQuery { Query: "mammal OR entity OR any" })
+        if err != nil {
+            t.Fatalf("can't search: %v", err)
+        }
+        // equal scores are ranked by part of speech and offset
+        for j := 1; j < len(results); j++ {
+            if results[j].Score == results[j - 1].Score &&
+                !getSynsetKey(results[j - 1].Synset).less(getSynsetKey(results[j].Synset)) {
+                t.Errorf("expected %v before %v", results[j].Synset.Words, results[j - 1].Synset.Words)
+            }
+        }
+        if first == nil {
+            first = results
+        } else if !reflect.DeepEqual(results, first) {
+            t.Fatalf("expected the same results every time")
+        }
+    }
+}
+
+func BenchmarkSearch(b *testing.B) {
+    wn, _ := loadSystemWordNet(b)
+    wn.BuildSearchIndex(false)
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        wn.Search(SearchQuery { Query: "enzyme OR \"amino acid\"" })
+    }
+}
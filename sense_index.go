@@ -1,11 +1,9 @@
 package gown
 
 import (
-    "bufio"
     "fmt"
     "io"
-    "os"
-    "strconv"
+    "io/fs"
     "strings"
 )
 
@@ -35,6 +33,9 @@ type SenseIndexEntry struct {
     SynsetOffset int       // byte offset into <POS>.data file
     SenseNumber int        // sense number within the <POS>.data for the word
     TagCount int           // number of times the word was tagged in semantic concordance texts
+    SenseKey SenseKey      // the sense key the fields above were parsed from
+    SyntacticMarker int    // syntactic marker of an adjective in its synset. (e.g. SYNTACTIC_MARKER_PREDICATE_POSITION)
+    Orthography string     // the lemma as it's written in its synset, which keeps its case. (e.g. "Mars")
     synsetPtr *Synset      // back ponter to the underlying synset.
 }
 
@@ -71,63 +72,33 @@ func (e *SenseIndexEntry) GetSynsetPtr() *Synset {
     return e.synsetPtr
 }
 
-func loadSenseIndex(wn *WN, senseIndexFilename string) (senseIndex, error) {
-    index := senseIndex{}
-
-    infile, err := os.Open(senseIndexFilename)
-    defer infile.Close()
+func loadSenseIndex(wn *WN, fsys fs.FS, senseIndexFilename string) (senseIndex, error) {
+    infile, err := openDictFile(fsys, senseIndexFilename)
     if err != nil {
         return nil, fmt.Errorf("can't open %s: %v", senseIndexFilename, err)
     }
-    r := bufio.NewReader(infile)
-    if (r == nil) {
-        return nil, fmt.Errorf("can't read %s: %v" + senseIndexFilename, err)
-    }
+    defer infile.Close()
 
-    var readerr error = nil
-    for ; readerr == nil ; {
-        bytebuf, readerr := r.ReadBytes('\n')
-        if readerr != nil && readerr != io.EOF {
-            panic(readerr)
+    index := senseIndex{}
+    lr := newLineReader(infile, senseIndexFilename)
+    for {
+        line, err := lr.next()
+        if err == io.EOF {
+            break
         }
-        if len(bytebuf) == 0 {
-            break;
+        if err != nil {
+            return nil, err
         }
-
-        fields := strings.Split(strings.TrimSpace(string(bytebuf)), " ")
-        sense_key := fields[0]
-        synset_offset, _ := strconv.Atoi(fields[1])     // byte offset into <POS> data file
-        sense_number, _ := strconv.Atoi(fields[2])      // sense number within the POS for the word
-        tag_cnt, _ := strconv.Atoi(fields[3])           // number of times the word was tagged in semantic concordance texts
-
-        // now parse the sense key
-        sense_key_fields := strings.Split(sense_key, "%")
-        lemma := readStoredLemma(sense_key_fields[0])
-        lex_sense_fields := strings.Split(sense_key_fields[1], ":")
-        ss_type, _ := strconv.Atoi(lex_sense_fields[0])     // POS tag. (e.g. POS_NOUN, ...)
-        lex_filenum, _ := strconv.Atoi(lex_sense_fields[1]) // index into LEXOGRAPHER_FILE_NUM_TO_NAME
-        lex_id, _ := strconv.Atoi(lex_sense_fields[2])      // identifies a sense within a lemma file (default is 0)
-        head_word := lex_sense_fields[3]                    // OPTIONAL lemma of the first word of the adjective satellite's head synset. (ss_type of this entry is 5)
-        head_id, _ := strconv.Atoi(lex_sense_fields[4])     // OPTIONAL uniquely identifies head_word in a lexographer file. ( fmt.Sprintf("%s%2d", head_word, head_id) )
-
-        var synsetPtr *Synset = nil
-        if wn != nil {
-            synsetPtr = wn.GetSynset(ss_type, synset_offset)
+        if len(strings.TrimSpace(line)) == 0 {
+            continue
         }
 
-        newEntry := SenseIndexEntry {
-            lemma,
-            ss_type,
-            lex_filenum,
-            lex_id,
-            head_word,
-            head_id,
-            synset_offset,
-            sense_number,
-            tag_cnt,
-            synsetPtr,
+        newEntry, err := parseSenseIndexLine(wn, lr.fields(line))
+        if err != nil {
+            return nil, err
         }
 
+        lemma := newEntry.Lemma
         entries, exists := index[lemma]
         if !exists {
             index[lemma] = make([]SenseIndexEntry, 1)
@@ -139,3 +110,54 @@ func loadSenseIndex(wn *WN, senseIndexFilename string) (senseIndex, error) {
 
     return index, nil
 }
+
+// Parses one line of index.sense. The format is:
+// sense_key  synset_offset  sense_number  tag_cnt
+// If wn is not nil, the entry points back to its synset.
+func parseSenseIndexLine(wn *WN, f *fieldReader) (SenseIndexEntry, error) {
+    sense_key := f.str("sense_key")
+    synset_offset := f.decimal("synset_offset")    // byte offset into <POS> data file
+    sense_number := f.decimal("sense_number")      // sense number within the POS for the word
+    tag_cnt := f.decimal("tag_cnt")                // number of times the word was tagged in semantic concordance texts
+    if f.err != nil {
+        return SenseIndexEntry{}, f.err
+    }
+
+    key, err := ParseSenseKey(sense_key)
+    if err != nil {
+        return SenseIndexEntry{}, newFieldError(f.filename, f.lineNumber, "sense_key", sense_key)
+    }
+
+    var synsetPtr *Synset = nil
+    orthography := key.Lemma
+    marker := SYNTACTIC_MARKER_NOT_APPLICABLE
+    if wn != nil {
+        synsetPtr = wn.GetSynset(key.PartOfSpeech, synset_offset)
+        wordIndex := -1
+        if synsetPtr != nil {
+            wordIndex = synsetPtr.wordIndex(key.Lemma)
+        }
+        if wordIndex >= 0 {
+            orthography = synsetPtr.Words[wordIndex]
+        }
+        if wordIndex >= 0 && wordIndex < len(synsetPtr.Markers) {
+            marker = synsetPtr.Markers[wordIndex]
+        }
+    }
+
+    return SenseIndexEntry {
+        key.Lemma,
+        key.PartOfSpeech,
+        key.LexographerFilenum,
+        key.LexId,
+        key.HeadWord,
+        key.HeadId,
+        synset_offset,
+        sense_number,
+        tag_cnt,
+        key,
+        marker,
+        orthography,
+        synsetPtr,
+    }, nil
+}
@@ -1,13 +1,13 @@
 package gown
 
 import (
+    "os"
     "testing"
 )
 
 func TestLoadSenseIndex(t *testing.T) {
     dictDir, _ := GetWordNetDictDir()
-    senseIndexFile := dictDir + "/index.sense"
-    senseIndex, err := loadSenseIndex(nil, senseIndexFile)
+    senseIndex, err := loadSenseIndex(nil, os.DirFS(dictDir), "index.sense")
     if senseIndex == nil {
         t.Fatalf("Failed to load sense index: %v", err)
     }
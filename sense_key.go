@@ -0,0 +1,117 @@
+package gown
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+/*
+From senseidx(5WN):
+
+A sense_key is represented as:
+    lemma % lex_sense
+where lex_sense is encoded as:
+    ss_type:lex_filenum:lex_id:head_word:head_id
+
+head_word and head_id are only present for adjective satellites. Sense keys
+are the most stable way to identify a word sense: unlike synset offsets,
+they don't change between WordNet versions unless the sense itself does.
+*/
+
+type SenseKey struct {
+    Lemma string           // lower case, with spaces rather than underscores
+    PartOfSpeech int       // POS tag. (e.g. POS_NOUN, ...)
+    LexographerFilenum int // index into LEXOGRAPHER_FILE_NUM_TO_NAME
+    LexId int              // identifies a sense within a lemma file (default is 0)
+    HeadWord string        // OPTIONAL lemma of the first word of the adjective satellite's head synset
+    HeadId int             // OPTIONAL identifies head_word in a lexographer file
+}
+
+// Parses a sense key, e.g. "live%5:00:00:charged:00".
+func ParseSenseKey(s string) (SenseKey, error) {
+    sense_key_fields := strings.Split(s, "%")
+    if len(sense_key_fields) != 2 || sense_key_fields[0] == "" {
+        return SenseKey{}, fmt.Errorf("malformed sense key %q", s)
+    }
+    lex_sense_fields := strings.Split(sense_key_fields[1], ":")
+    if len(lex_sense_fields) != 5 {
+        return SenseKey{}, fmt.Errorf("malformed sense key %q", s)
+    }
+    ss_type, err1 := strconv.Atoi(lex_sense_fields[0])
+    lex_filenum, err2 := strconv.Atoi(lex_sense_fields[1])
+    lex_id, err3 := strconv.Atoi(lex_sense_fields[2])
+    head_word := lex_sense_fields[3]
+    head_id := 0
+    var err4 error = nil
+    if lex_sense_fields[4] != "" {
+        head_id, err4 = strconv.Atoi(lex_sense_fields[4])
+    }
+    if err1 != nil || err2 != nil || err3 != nil || err4 != nil || ss_type < POS_NOUN || ss_type > POS_ADJECTIVE_SATELLITE {
+        return SenseKey{}, fmt.Errorf("malformed sense key %q", s)
+    }
+    // index.sense is in lower case, so keys written in any case compare
+    // equal to its keys
+    return SenseKey {
+        strings.ToLower(readStoredLemma(sense_key_fields[0])),
+        ss_type,
+        lex_filenum,
+        lex_id,
+        strings.ToLower(head_word),
+        head_id,
+    }, nil
+}
+
+// Formats the sense key as it appears in index.sense.
+func (k SenseKey) String() string {
+    head_id := ""
+    if k.HeadWord != "" {
+        head_id = fmt.Sprintf("%02d", k.HeadId)
+    }
+    return fmt.Sprintf("%s%%%d:%02d:%02d:%s:%s",
+        strings.ToLower(writeStoredLemma(k.Lemma)),
+        k.PartOfSpeech,
+        k.LexographerFilenum,
+        k.LexId,
+        k.HeadWord,
+        head_id)
+}
+
+// Returns the sense with the sense key, or nil if there is none.
+func (wn *WN) LookupSenseKey(key SenseKey) *SenseIndexEntry {
+    senses := wn.senses(strings.ToLower(key.Lemma))
+    for i, _ := range senses {
+        if senses[i].SenseKey == key {
+            return &senses[i]
+        }
+    }
+    return nil
+}
+
+// Returns the sense keys of the words of a synset, in the order of
+// synset.Words.
+func (wn *WN) SenseKeys(synset *Synset) []SenseKey {
+    ret := make([]SenseKey, len(synset.Words))
+    for i, _ := range synset.Words {
+        ret[i] = wn.senseKey(synset, i)
+    }
+    return ret
+}
+
+// Returns the sense key of a word of a synset. wordIndex is the zero based
+// index into synset.Words.
+func (wn *WN) senseKey(synset *Synset, wordIndex int) SenseKey {
+    lemma := strings.ToLower(synset.Words[wordIndex])
+    for _, sense := range wn.senses(lemma) {
+        if sense.SynsetOffset == synset.SynsetOffset && normalizePos(sense.PartOfSpeech) == normalizePos(synset.PartOfSpeech) {
+            return sense.SenseKey
+        }
+    }
+    // not in the sense index, so build it from the synset. (without the
+    // head of an adjective satellite)
+    lexId := 0
+    if wordIndex < len(synset.LexIds) {
+        lexId = synset.LexIds[wordIndex]
+    }
+    return SenseKey { lemma, synset.PartOfSpeech, synset.LexographerFilenum, lexId, "", 0 }
+}
@@ -0,0 +1,86 @@
+package gown
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestParseSenseKey(t *testing.T) {
+    tests := []struct {
+        key string
+        expected SenseKey
+    } {
+        { "dog%1:05:00::", SenseKey { "dog", POS_NOUN, 5, 0, "", 0 } },
+        { "live%5:00:00:charged:00", SenseKey { "live", POS_ADJECTIVE_SATELLITE, 0, 0, "charged", 0 } },
+        { "attorney_general%1:18:00::", SenseKey { "attorney general", POS_NOUN, 18, 0, "", 0 } },
+    }
+    for _, test := range tests {
+        key, err := ParseSenseKey(test.key)
+        if err != nil {
+            t.Errorf("can't parse %s: %v", test.key, err)
+            continue
+        }
+        if key != test.expected {
+            t.Errorf("expected %s to parse as %v, got %v", test.key, test.expected, key)
+        }
+        if key.String() != test.key {
+            t.Errorf("expected %v to format as %s, got %s", key, test.key, key.String())
+        }
+    }
+
+    // keys are lower case, like in index.sense
+    key, err := ParseSenseKey("Live%5:00:00:Charged:00")
+    if err != nil || key != (SenseKey { "live", POS_ADJECTIVE_SATELLITE, 0, 0, "charged", 0 }) {
+        t.Errorf("expected Live%%5:00:00:Charged:00 to parse in lower case, got %v %v", key, err)
+    }
+    for _, malformed := range []string { "", "dog", "dog%1:05:00:", "dog%x:05:00::", "dog%9:05:00::", "%1:05:00::" } {
+        if _, err := ParseSenseKey(malformed); err == nil {
+            t.Errorf("expected an error parsing %q", malformed)
+        }
+    }
+}
+
+func TestLookupSenseKey(t *testing.T) {
+    forEachLoadMode(t, LoadOptions{}, func(t *testing.T, wn *WN) {
+        key, _ := ParseSenseKey("dog%1:05:00::")
+        sense := wn.LookupSenseKey(key)
+        if sense == nil {
+            t.Fatalf("expected to find %s", key)
+        }
+        if sense.Lemma != "dog" || sense.PartOfSpeech != POS_NOUN || sense.SenseNumber != 1 {
+            t.Errorf("unexpected sense for %s: %s", key, sense.ToString())
+        }
+        if sense.SenseKey != key {
+            t.Errorf("expected the sense to have the key %s, got %s", key, sense.SenseKey)
+        }
+
+        mars, _ := ParseSenseKey("Mars%1:17:00::")
+        marsSense := wn.LookupSenseKey(mars)
+        if marsSense == nil || marsSense.SenseKey.String() != "mars%1:17:00::" {
+            t.Errorf("expected to find %s", mars)
+        }
+
+        // every sense can be found by its key
+        for _, sense := range wn.Lookup("live") {
+            found := wn.LookupSenseKey(sense.SenseKey)
+            if found == nil || found.SynsetOffset != sense.SynsetOffset {
+                t.Errorf("expected to find %s", sense.SenseKey)
+            }
+        }
+
+        key.LexId = 99
+        if wn.LookupSenseKey(key) != nil {
+            t.Errorf("expected no sense for %s", key)
+        }
+
+        dog := sense.GetSynsetPtr()
+        keys := []string{}
+        for _, k := range wn.SenseKeys(dog) {
+            keys = append(keys, k.String())
+        }
+        expected := []string { "dog%1:05:00::", "domestic_dog%1:05:00::", "canis_familiaris%1:05:00::" }
+        if !reflect.DeepEqual(keys, expected) {
+            t.Errorf("expected the sense keys of dog to be %v, got %v", expected, keys)
+        }
+    })
+}
@@ -0,0 +1,272 @@
+package gown
+
+import (
+    "math"
+    "sort"
+)
+
+/*
+Semantic similarity measures over the noun and verb hypernym hierarchies.
+Hypernyms are found by following both HYPERNYM_RELATIONSHIP and
+INSTANCE_HYPERNYM_RELATIONSHIP edges. The measures, and the handling of the
+virtual root node, follow NLTK so the scores match its reference values.
+
+Verbs (and, in WordNet 3.x, everything but nouns) have many unique
+beginners, so two synsets may not share a hypernym. When simulateRoot is
+true, a virtual root node is placed above every unique beginner of those
+parts of speech so that a path always exists.
+*/
+
+// identifies a synset. adjective satellites live in the adjective data
+// file, so they share its offsets.
+type synsetKey struct {
+    pos int
+    offset int
+}
+
+// the virtual root that sits above every unique beginner
+var virtualRootKey = synsetKey { POS_UNSUPPORTED, -1 }
+
+func getSynsetKey(s *Synset) synsetKey {
+    return synsetKey { normalizePos(s.PartOfSpeech), s.SynsetOffset }
+}
+
+// orders keys by part of speech, then offset
+func (k synsetKey) less(other synsetKey) bool {
+    if k.pos != other.pos {
+        return k.pos < other.pos
+    }
+    return k.offset < other.offset
+}
+
+// Returns 1 / (shortest path length + 1) between the two synsets. The
+// second return value is false if there is no path between them.
+func (wn *WN) PathSimilarity(s1 *Synset, s2 *Synset, simulateRoot bool) (float64, bool) {
+    needRoot := needsRoot(s1.PartOfSpeech) || needsRoot(s2.PartOfSpeech)
+    distance, connected := wn.ShortestPathDistance(s1, s2, simulateRoot && needRoot)
+    if !connected {
+        return 0, false
+    }
+    return 1.0 / float64(distance + 1), true
+}
+
+// Returns the Wu-Palmer similarity, 2 * depth(lcs) / (len1 + len2), where
+// the lengths are the distances of each synset from the root through
+// the lowest common subsumer. The second return value is false if the
+// synsets have no common subsumer.
+func (wn *WN) WuPalmerSimilarity(s1 *Synset, s2 *Synset, simulateRoot bool) (float64, bool) {
+    needRoot := needsRoot(s1.PartOfSpeech) || needsRoot(s2.PartOfSpeech)
+    simulate := simulateRoot && needRoot
+
+    // like NLTK, use the minimum depth to pick the subsumer
+    subsumers := wn.lowestCommonHypernymKeys(s1, s2, simulate, true)
+    if len(subsumers) == 0 {
+        return 0, false
+    }
+    subsumer := subsumers[0]
+    for _, k := range subsumers {
+        if k == getSynsetKey(s1) {
+            subsumer = k
+        }
+    }
+
+    depth := wn.keyMaxDepth(subsumer, map[synsetKey]int{}) + 1
+    subsumerDistances := wn.keyHypernymDistances(subsumer, simulate)
+    len1 := pathDistance(wn.hypernymDistances(s1, simulate), subsumerDistances)
+    len2 := pathDistance(wn.hypernymDistances(s2, simulate), subsumerDistances)
+    if len1 < 0 || len2 < 0 {
+        return 0, false
+    }
+    return (2.0 * float64(depth)) / float64(len1 + depth + len2 + depth), true
+}
+
+// Returns the Leacock-Chodorow similarity, -log((distance + 1) / (2 * D)),
+// where D is the depth of the taxonomy for the part of speech. Like NLTK,
+// D counts the virtual root for parts of speech that need one whether or
+// not simulateRoot is set, which only affects the distance. The synsets
+// must have the same part of speech. The second return value is false if
+// there is no path between them.
+func (wn *WN) LeacockChodorowSimilarity(s1 *Synset, s2 *Synset, simulateRoot bool) (float64, bool) {
+    pos := getSynsetKey(s1).pos
+    if pos != getSynsetKey(s2).pos {
+        return 0, false
+    }
+    needRoot := needsRoot(pos)
+
+    depth := wn.taxonomyDepth(pos)
+    if needRoot {
+        depth++
+    }
+    distance, connected := wn.ShortestPathDistance(s1, s2, simulateRoot && needRoot)
+    if !connected || depth == 0 {
+        return 0, false
+    }
+    return -math.Log(float64(distance + 1) / (2.0 * float64(depth))), true
+}
+
+// nouns share a single unique beginner ("entity"). everything else needs
+// the virtual root to guarantee a path.
+func needsRoot(pos int) bool {
+    return pos != POS_NOUN
+}
+
+func pathDistance(distances1 map[synsetKey]int, distances2 map[synsetKey]int) int {
+    best := -1
+    for k, d1 := range distances1 {
+        d2, exists := distances2[k]
+        if exists && (best < 0 || d1 + d2 < best) {
+            best = d1 + d2
+        }
+    }
+    return best
+}
+
+// Returns the shortest distance from the synset to each of its hypernyms,
+// including itself at distance 0. With simulateRoot, the virtual root is
+// one step above the most distant hypernym.
+func (wn *WN) hypernymDistances(s *Synset, simulateRoot bool) map[synsetKey]int {
+    distances := map[synsetKey]int{}
+    maxDistance := 0
+    wn.Walk(s, HYPERNYM_RELATIONSHIPS, -1, func(hypernym *Synset, depth int) bool {
+        distances[getSynsetKey(hypernym)] = depth
+        if depth > maxDistance {
+            maxDistance = depth
+        }
+        return true
+    })
+    if simulateRoot {
+        distances[virtualRootKey] = maxDistance + 1
+    }
+    return distances
+}
+
+func (wn *WN) keyHypernymDistances(k synsetKey, simulateRoot bool) map[synsetKey]int {
+    if k == virtualRootKey {
+        return map[synsetKey]int { virtualRootKey: 0 }
+    }
+    s := wn.GetSynset(k.pos, k.offset)
+    if s == nil {
+        return map[synsetKey]int{}
+    }
+    return wn.hypernymDistances(s, simulateRoot)
+}
+
+// Returns the deepest common hypernyms of the two synsets (which may
+// include either synset itself), sorted by name like NLTK sorts them, so
+// that callers taking the first one pick the same one. (see SynsetName)
+// Depth is measured as the minimum or maximum depth depending on
+// useMinDepth.
+func (wn *WN) lowestCommonHypernymKeys(s1 *Synset, s2 *Synset, simulateRoot bool, useMinDepth bool) []synsetKey {
+    distances1 := wn.hypernymDistances(s1, false)
+    distances2 := wn.hypernymDistances(s2, false)
+    common := []synsetKey{}
+    for k, _ := range distances1 {
+        if _, exists := distances2[k]; exists {
+            common = append(common, k)
+        }
+    }
+    if simulateRoot {
+        common = append(common, virtualRootKey)
+    }
+
+    memo := map[synsetKey]int{}
+    depths := make([]int, len(common))
+    deepest := -1
+    for i, k := range common {
+        if useMinDepth {
+            depths[i] = wn.keyMinDepth(k, memo)
+        } else {
+            depths[i] = wn.keyMaxDepth(k, memo)
+        }
+        if depths[i] > deepest {
+            deepest = depths[i]
+        }
+    }
+
+    ret := []synsetKey{}
+    for i, k := range common {
+        if depths[i] == deepest {
+            ret = append(ret, k)
+        }
+    }
+    names := map[synsetKey]string { virtualRootKey: "*ROOT*" }
+    for _, k := range ret {
+        if synset := wn.GetSynset(k.pos, k.offset); synset != nil && k != virtualRootKey {
+            names[k] = wn.SynsetName(synset)
+        }
+    }
+    sort.Slice(ret, func(i, j int) bool {
+        if names[ret[i]] != names[ret[j]] {
+            return names[ret[i]] < names[ret[j]]
+        }
+        return ret[i].less(ret[j])
+    })
+    return ret
+}
+
+// Returns the length of the shortest hypernym path from the synset to a
+// unique beginner.
+func (wn *WN) keyMinDepth(k synsetKey, memo map[synsetKey]int) int {
+    return wn.keyDepth(k, memo, false)
+}
+
+// Returns the length of the longest hypernym path from the synset to a
+// unique beginner.
+func (wn *WN) keyMaxDepth(k synsetKey, memo map[synsetKey]int) int {
+    return wn.keyDepth(k, memo, true)
+}
+
+func (wn *WN) keyDepth(k synsetKey, memo map[synsetKey]int, longest bool) int {
+    if k == virtualRootKey {
+        return 0
+    }
+    if depth, exists := memo[k]; exists {
+        return depth
+    }
+    s := wn.GetSynset(k.pos, k.offset)
+    if s == nil {
+        return 0
+    }
+    depth := -1
+    for _, hypernym := range wn.Related(s, HYPERNYM_RELATIONSHIPS) {
+        d := wn.keyDepth(getSynsetKey(hypernym), memo, longest) + 1
+        if depth < 0 || (longest && d > depth) || (!longest && d < depth) {
+            depth = d
+        }
+    }
+    if depth < 0 {
+        depth = 0
+    }
+    memo[k] = depth
+    return depth
+}
+
+// Returns the maximum depth of any synset of the part of speech. It's
+// computed on first use and cached.
+func (wn *WN) taxonomyDepth(pos int) int {
+    wn.taxonomyDepthsLock.Lock()
+    defer wn.taxonomyDepthsLock.Unlock()
+
+    if wn.taxonomyDepths == nil {
+        wn.taxonomyDepths = map[int]int{}
+    }
+    depth, exists := wn.taxonomyDepths[pos]
+    if exists {
+        return depth
+    }
+
+    depth = 0
+    memo := map[synsetKey]int{}
+    for synset := range wn.Iter() {
+        k := getSynsetKey(synset)
+        if k.pos != pos {
+            continue
+        }
+        d := wn.keyMaxDepth(k, memo)
+        if d > depth {
+            depth = d
+        }
+    }
+    wn.taxonomyDepths[pos] = depth
+    return depth
+}
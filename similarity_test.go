@@ -0,0 +1,100 @@
+package gown
+
+import (
+    "math"
+    "reflect"
+    "testing"
+)
+
+func TestSimilarity(t *testing.T) {
+    wn, _ := loadSystemWordNet(t)
+
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+    cat := wn.LookupWithPartOfSpeechAndSense("cat", POS_NOUN, 1).GetSynsetPtr()
+    hit := wn.LookupWithPartOfSpeechAndSense("hit", POS_VERB, 1).GetSynsetPtr()
+    slap := wn.LookupWithPartOfSpeechAndSense("slap", POS_VERB, 1).GetSynsetPtr()
+
+    // reference values from NLTK on WordNet 3.0
+    measures := []string { "path", "path", "wup", "wup", "lch", "lch" }
+    actuals := []float64 {
+        mustSimilarity(t, wn.PathSimilarity, dog, cat),
+        mustSimilarity(t, wn.PathSimilarity, hit, slap),
+        mustSimilarity(t, wn.WuPalmerSimilarity, dog, cat),
+        mustSimilarity(t, wn.WuPalmerSimilarity, hit, slap),
+        mustSimilarity(t, wn.LeacockChodorowSimilarity, dog, cat),
+        mustSimilarity(t, wn.LeacockChodorowSimilarity, hit, slap),
+    }
+    expecteds := []float64 {
+        0.2,
+        0.14285714285714285,
+        0.8571428571428571,
+        0.25,
+        2.0281482472922856,
+        1.3121863889661687,
+    }
+
+    for i, expected := range expecteds {
+        if math.Abs(actuals[i] - expected) > 1e-9 {
+            t.Errorf("%s: expected %v but got %v\n", measures[i], expected, actuals[i])
+        }
+    }
+
+    if _, ok := wn.PathSimilarity(dog, hit, false); ok {
+        t.Errorf("expected no path between a noun and a verb without a virtual root")
+    }
+}
+
+func mustSimilarity(t *testing.T, measure func(*Synset, *Synset, bool) (float64, bool), s1 *Synset, s2 *Synset) float64 {
+    similarity, ok := measure(s1, s2, true)
+    if !ok {
+        t.Fatalf("no similarity between %v and %v", s1.Words, s2.Words)
+    }
+    return similarity
+}
+
+func TestLeacockChodorowVerbDepth(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    walk := wn.LookupWithPartOfSpeechAndSense("walk", POS_VERB, 1).GetSynsetPtr()
+    jump := wn.LookupWithPartOfSpeechAndSense("jump", POS_VERB, 1).GetSynsetPtr()
+
+    // like NLTK, the depth counts the virtual root of the verbs either way:
+    // -log((2 + 1) / (2 * (2 + 1)))
+    for _, simulateRoot := range []bool { true, false } {
+        similarity, ok := wn.LeacockChodorowSimilarity(walk, jump, simulateRoot)
+        if !ok || math.Abs(similarity - math.Log(2)) > 1e-9 {
+            t.Errorf("expected %v with simulateRoot %v, got %v", math.Log(2), simulateRoot, similarity)
+        }
+    }
+}
+
+func TestLowestCommonHypernymTies(t *testing.T) {
+    forEachLoadMode(t, LoadOptions{}, func(t *testing.T, wn *WN) {
+        synset := func(lemma string) *Synset {
+            return wn.LookupWithPartOfSpeechAndSense(lemma, POS_NOUN, 1).GetSynsetPtr()
+        }
+        // living thing and artifact are both kinds of whole, and now of axis,
+        // which is as deep as whole but comes after it in the data file
+        axis := synset("axis")
+        edge := RelationshipEdge { HYPERNYM_RELATIONSHIP, axis.SynsetOffset, POS_NOUN, 0, 0 }
+        wn.addRelationships(map[synsetKey][]RelationshipEdge {
+            getSynsetKey(synset("living thing")): { edge },
+            getSynsetKey(synset("artifact")): { edge },
+        })
+        living := synset("living thing")
+        artifact := synset("artifact")
+
+        // NLTK sorts the ties by name, so axis.n.01 comes first
+        common := wn.LowestCommonHypernyms(living, artifact)
+        names := []string{}
+        for _, s := range common {
+            names = append(names, wn.SynsetName(s))
+        }
+        if !reflect.DeepEqual(names, []string { "axis.n.01", "whole.n.01" }) {
+            t.Errorf("expected axis and whole as the lowest common hypernyms, got %v", names)
+        }
+        subsumers := wn.lowestCommonHypernymKeys(living, artifact, true, true)
+        if len(subsumers) == 0 || subsumers[0] != getSynsetKey(axis) {
+            t.Errorf("expected axis as the subsumer used by WuPalmerSimilarity, got %v", subsumers)
+        }
+    })
+}
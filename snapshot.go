@@ -0,0 +1,1041 @@
+package gown
+
+import (
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "sort"
+    "strings"
+)
+
+/*
+A snapshot is a WN written to a single file that is read in place, so
+loading one parses nothing up front. It starts with the 8 byte
+SNAPSHOT_MAGIC, a big endian uint32 SNAPSHOT_VERSION and the uint32 size of
+the directory that follows, which names each section and gives its offset
+and size as big endian uint64s.
+
+Most sections are tables of records sorted by a key, found by binary search
+through an offset table: a uint32 record count n, n + 1 uint32 offsets of
+the records from the end of the offsets, then the records. Records are made
+of varints and length prefixed strings, starting with their key. Synsets,
+index entries, senses, tagged glosses and ILI ids are decoded one at a time
+when they're used, and synsets are cached like in lazy mode. Only the small
+sections (the morphology exceptions and verb frames) are decoded at load
+time.
+
+The sections are:
+    index.noun, index.verb, index.adj, index.adv   index entries, by lemma
+    data.noun, data.verb, data.adj, data.adv       synsets, by offset
+    index.sense                                    senses, by lemma
+    glosstag                                       tagged glosses, by synset
+    ili.synset, ili.id                             ILI ids, by synset and by id
+    exceptions                                     morphology exceptions
+    frames                                         verb frames and sentences
+
+The version is bumped whenever the layout of a section changes.
+*/
+
+const SNAPSHOT_MAGIC string = "GOWNSNAP"
+const SNAPSHOT_VERSION uint32 = 7
+
+var (
+    ErrNotSnapshot = errors.New("not a gown snapshot")
+    ErrSnapshotVersion = errors.New("unsupported snapshot version")
+    ErrSnapshotCorrupt = errors.New("corrupt snapshot")
+)
+
+var SNAPSHOT_POS_FILE_NAMES = []string { "", "noun", "verb", "adj", "adv" }
+
+type snapshotSection struct {
+    name string
+    offset int64
+    size int64
+}
+
+// A snapshot read in place.
+type snapshotReader struct {
+    r io.ReaderAt
+    closer io.Closer        // the file opened by LoadSnapshot, if any
+    indexTables map[int]*snapshotTable
+    dataTables map[int]*snapshotTable
+    senseTable *snapshotTable
+    glossTagTable *snapshotTable
+    iliSynsetTable *snapshotTable
+    iliTable *snapshotTable
+}
+
+// Writes a snapshot of the database to w. Call InitMorphData first to
+// include the morphology exception lists. The tagged glosses and ILI
+// mapping are included if they're loaded, and so are the edges added by
+// AddInverseRelationships and ConnectVerbGroups. Indices built by the
+// Build methods aren't, and need to be built again after loading.
+func (wn *WN) WriteSnapshot(w io.Writer) error {
+    sections := []string{}
+    contents := [][]byte{}
+    addSection := func(name string, content []byte, err error) error {
+        if err != nil {
+            return fmt.Errorf("can't write the %s section: %v", name, err)
+        }
+        sections = append(sections, name)
+        contents = append(contents, content)
+        return nil
+    }
+
+    for pos := POS_NOUN; pos <= POS_ADVERB; pos++ {
+        name := "index." + SNAPSHOT_POS_FILE_NAMES[pos]
+        content, err := wn.snapshotIndex(pos)
+        if err := addSection(name, content, err); err != nil {
+            return err
+        }
+    }
+    synsets := map[int][]*Synset{}
+    for synset := range wn.Iter() {
+        pos := normalizePos(synset.PartOfSpeech)
+        synsets[pos] = append(synsets[pos], synset)
+    }
+    for pos := POS_NOUN; pos <= POS_ADVERB; pos++ {
+        name := "data." + SNAPSHOT_POS_FILE_NAMES[pos]
+        content, err := snapshotSynsets(synsets[pos])
+        if err := addSection(name, content, err); err != nil {
+            return err
+        }
+    }
+    content, err := wn.snapshotSenses()
+    if err := addSection("index.sense", content, err); err != nil {
+        return err
+    }
+    content, err = wn.snapshotGlossTags()
+    if err := addSection("glosstag", content, err); err != nil {
+        return err
+    }
+    synsetContent, idContent, err := wn.snapshotILI()
+    if err := addSection("ili.synset", synsetContent, err); err != nil {
+        return err
+    }
+    if err := addSection("ili.id", idContent, nil); err != nil {
+        return err
+    }
+    if err := addSection("exceptions", wn.snapshotExceptions(), nil); err != nil {
+        return err
+    }
+    if err := addSection("frames", wn.snapshotVerbFrames(), nil); err != nil {
+        return err
+    }
+
+    // the size of the directory doesn't depend on the offsets in it
+    directory := snapshotDirectory(sections, contents, 0)
+    directory = snapshotDirectory(sections, contents, int64(len(SNAPSHOT_MAGIC) + 8 + len(directory)))
+    header := make([]byte, 8)
+    binary.BigEndian.PutUint32(header[0:], SNAPSHOT_VERSION)
+    binary.BigEndian.PutUint32(header[4:], uint32(len(directory)))
+
+    if _, err := io.WriteString(w, SNAPSHOT_MAGIC); err != nil {
+        return err
+    }
+    if _, err := w.Write(header); err != nil {
+        return err
+    }
+    if _, err := w.Write(directory); err != nil {
+        return err
+    }
+    for _, content := range contents {
+        if _, err := w.Write(content); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// Encodes the directory of the sections, which start at offset.
+func snapshotDirectory(sections []string, contents [][]byte, offset int64) []byte {
+    e := &snapshotEncoder{}
+    e.int(len(sections))
+    for i, name := range sections {
+        e.str(name)
+        e.uint64(uint64(offset))
+        e.uint64(uint64(len(contents[i])))
+        offset += int64(len(contents[i]))
+    }
+    return e.buf
+}
+
+// Writes a snapshot of the database to a file. (see WriteSnapshot)
+func (wn *WN) WriteSnapshotFile(snapshotFilename string) error {
+    outfile, err := os.Create(snapshotFilename)
+    if err != nil {
+        return err
+    }
+    err = wn.WriteSnapshot(outfile)
+    if cerr := outfile.Close(); err == nil {
+        err = cerr
+    }
+    return err
+}
+
+// Loads a database snapshot written by WriteSnapshot. The file is read in
+// place, so call Close when done with it.
+func LoadSnapshot(snapshotFilename string) (*WN, error) {
+    return LoadSnapshotWithOptions(snapshotFilename, LoadOptions{})
+}
+
+// Like LoadSnapshot, with the cache size and post-load passes of opts.
+// (opts.Lazy doesn't matter, since a snapshot is always read in place)
+func LoadSnapshotWithOptions(snapshotFilename string, opts LoadOptions) (*WN, error) {
+    infile, err := os.Open(snapshotFilename)
+    if err != nil {
+        return nil, fmt.Errorf("can't open %s: %v", snapshotFilename, err)
+    }
+    info, err := infile.Stat()
+    if err != nil {
+        infile.Close()
+        return nil, fmt.Errorf("can't open %s: %v", snapshotFilename, err)
+    }
+    wn, err := ReadSnapshot(infile, info.Size(), opts)
+    if err != nil {
+        infile.Close()
+        return nil, &ParseError { Filename: snapshotFilename, Err: err }
+    }
+    wn.snapshot.closer = infile
+    return wn, nil
+}
+
+// Reads a database snapshot written by WriteSnapshot from the size bytes
+// of r. r is read in place, so it must stay readable until the WN is no
+// longer used; it can be a memory mapped file, such as a bytes.Reader over
+// the mapping.
+func ReadSnapshot(r io.ReaderAt, size int64, opts LoadOptions) (*WN, error) {
+    header := make([]byte, len(SNAPSHOT_MAGIC) + 8)
+    if _, err := r.ReadAt(header, 0); err != nil || string(header[:len(SNAPSHOT_MAGIC)]) != SNAPSHOT_MAGIC {
+        return nil, ErrNotSnapshot
+    }
+    version := binary.BigEndian.Uint32(header[len(SNAPSHOT_MAGIC):])
+    if version != SNAPSHOT_VERSION {
+        return nil, fmt.Errorf("%w %d (expected %d)", ErrSnapshotVersion, version, SNAPSHOT_VERSION)
+    }
+    directorySize := int64(binary.BigEndian.Uint32(header[len(SNAPSHOT_MAGIC) + 4:]))
+    if int64(len(header)) + directorySize > size {
+        return nil, fmt.Errorf("%w: truncated directory", ErrSnapshotCorrupt)
+    }
+    directory := make([]byte, directorySize)
+    if _, err := r.ReadAt(directory, int64(len(header))); err != nil {
+        return nil, err
+    }
+
+    sections := map[string]snapshotSection{}
+    d := &snapshotDecoder { buf: directory }
+    count := d.int()
+    for i := 0; i < count && d.err == nil; i++ {
+        section := snapshotSection { d.str(), int64(d.uint64()), int64(d.uint64()) }
+        if section.offset < 0 || section.size < 0 || section.offset + section.size > size {
+            return nil, fmt.Errorf("%w: section %s is out of bounds", ErrSnapshotCorrupt, section.name)
+        }
+        sections[section.name] = section
+    }
+    if d.err != nil {
+        return nil, d.err
+    }
+
+    s := &snapshotReader {
+        r: r,
+        indexTables: map[int]*snapshotTable{},
+        dataTables: map[int]*snapshotTable{},
+    }
+    var err error = nil
+    openTable := func(name string) *snapshotTable {
+        if err != nil {
+            return nil
+        }
+        var table *snapshotTable
+        table, err = openSnapshotTable(r, sections, name)
+        return table
+    }
+    for pos := POS_NOUN; pos <= POS_ADVERB; pos++ {
+        s.indexTables[pos] = openTable("index." + SNAPSHOT_POS_FILE_NAMES[pos])
+        s.dataTables[pos] = openTable("data." + SNAPSHOT_POS_FILE_NAMES[pos])
+    }
+    s.senseTable = openTable("index.sense")
+    s.glossTagTable = openTable("glosstag")
+    s.iliSynsetTable = openTable("ili.synset")
+    s.iliTable = openTable("ili.id")
+    if err != nil {
+        return nil, err
+    }
+
+    wn := &WN {
+        PosIndicies: map[int]*dataIndex{},
+        posData: map[int]*dataFile{},
+        snapshot: s,
+    }
+    exceptions, err := readSnapshotSection(r, sections, "exceptions")
+    if err != nil {
+        return nil, err
+    }
+    if err := wn.readSnapshotExceptions(exceptions); err != nil {
+        return nil, err
+    }
+    frames, err := readSnapshotSection(r, sections, "frames")
+    if err != nil {
+        return nil, err
+    }
+    if err := wn.readSnapshotVerbFrames(frames); err != nil {
+        return nil, err
+    }
+
+    wn.lazy = newLazyData(s, opts.SynsetCacheSize)
+    wn.applyLoadOptions(opts)
+    return wn, nil
+}
+
+func readSnapshotSection(r io.ReaderAt, sections map[string]snapshotSection, name string) ([]byte, error) {
+    section, exists := sections[name]
+    if !exists {
+        return nil, fmt.Errorf("%w: no %s section", ErrSnapshotCorrupt, name)
+    }
+    content := make([]byte, section.size)
+    if _, err := r.ReadAt(content, section.offset); err != nil {
+        return nil, err
+    }
+    return content, nil
+}
+
+func (s *snapshotReader) Close() error {
+    if s.closer == nil {
+        return nil
+    }
+    err := s.closer.Close()
+    s.closer = nil
+    return err
+}
+
+// index entries: lemma, pos, synset count, relationships, tag sense count,
+// synset offsets
+
+func (wn *WN) snapshotIndex(pos int) ([]byte, error) {
+    pairs := []DataIndexPair{}
+    if entries := wn.TraverseDataIndex(pos); entries != nil {
+        for pair := range entries {
+            pairs = append(pairs, pair)
+        }
+    }
+    sort.Slice(pairs, func(i, j int) bool { return pairs[i].Lexeme < pairs[j].Lexeme })
+    table := &snapshotTableWriter{}
+    for _, pair := range pairs {
+        e := &snapshotEncoder{}
+        e.str(pair.Lexeme)
+        e.int(pair.IndexEntry.PartOfSpeech)
+        e.int(pair.IndexEntry.SynsetCount)
+        e.ints(pair.IndexEntry.Relationships)
+        e.int(pair.IndexEntry.TagSenseCount)
+        e.ints(pair.IndexEntry.SynsetOffsets)
+        table.add(e.buf)
+    }
+    return table.bytes()
+}
+
+func decodeSnapshotIndexEntry(d *snapshotDecoder) (DataIndexPair, bool) {
+    pair := DataIndexPair { d.str(), DataIndexEntry {
+        PartOfSpeech: d.int(),
+        SynsetCount: d.int(),
+        Relationships: d.ints(),
+        TagSenseCount: d.int(),
+        SynsetOffsets: d.ints(),
+    } }
+    return pair, d.err == nil
+}
+
+func (s *snapshotReader) readIndexEntry(pos int, lemma string) (DataIndexEntry, bool) {
+    table, exists := s.indexTables[pos]
+    if !exists {
+        return DataIndexEntry{}, false
+    }
+    lemma = strings.ToLower(lemma)
+    i := table.search(func(d *snapshotDecoder) bool { return d.str() >= lemma })
+    pair, ok := decodeSnapshotIndexEntry(table.record(i))
+    if !ok || pair.Lexeme != lemma {
+        return DataIndexEntry{}, false
+    }
+    return pair.IndexEntry, true
+}
+
+func (s *snapshotReader) eachIndexEntry(pos int, fn func(pair DataIndexPair) bool) {
+    table, exists := s.indexTables[pos]
+    if !exists {
+        return
+    }
+    for i := 0; i < table.count; i++ {
+        pair, ok := decodeSnapshotIndexEntry(table.record(i))
+        if ok && !fn(pair) {
+            return
+        }
+    }
+}
+
+func (s *snapshotReader) lemmasWithPrefix(prefix string, match func(lemma string) bool) []DataIndexPair {
+    ret := []DataIndexPair{}
+    for _, table := range s.indexTables {
+        i := table.search(func(d *snapshotDecoder) bool { return d.str() >= prefix })
+        for ; i < table.count; i++ {
+            pair, ok := decodeSnapshotIndexEntry(table.record(i))
+            if !ok || !strings.HasPrefix(pair.Lexeme, prefix) {
+                break
+            }
+            if match == nil || match(pair.Lexeme) {
+                ret = append(ret, pair)
+            }
+        }
+    }
+    return ret
+}
+
+// synsets: offset, lex filenum, pos, words, lex ids, markers, relationships,
+// frames (-1 if nil), gloss, definition, examples
+
+func snapshotSynsets(synsets []*Synset) ([]byte, error) {
+    sort.Slice(synsets, func(i, j int) bool { return synsets[i].SynsetOffset < synsets[j].SynsetOffset })
+    table := &snapshotTableWriter{}
+    for _, synset := range synsets {
+        e := &snapshotEncoder{}
+        e.int(synset.SynsetOffset)
+        e.int(synset.LexographerFilenum)
+        e.int(synset.PartOfSpeech)
+        e.strs(synset.Words)
+        e.ints(synset.LexIds)
+        e.ints(synset.Markers)
+        e.int(len(synset.Relationships))
+        for _, edge := range synset.Relationships {
+            e.int(edge.RelationshipType)
+            e.int(edge.SynsetOffset)
+            e.int(edge.PartOfSpeech)
+            e.int(edge.SourceWordNumber)
+            e.int(edge.TargetWordNumber)
+        }
+        if synset.Frames == nil {
+            e.int(-1)
+        } else {
+            e.int(len(synset.Frames))
+        }
+        for _, frame := range synset.Frames {
+            e.int(frame.FrameNumber)
+            e.int(frame.WordNumber)
+        }
+        e.str(synset.Gloss)
+        e.str(synset.Definition)
+        e.strs(synset.Examples)
+        table.add(e.buf)
+    }
+    return table.bytes()
+}
+
+func decodeSnapshotSynset(d *snapshotDecoder) (Synset, bool) {
+    synset := Synset {
+        SynsetOffset: d.int(),
+        LexographerFilenum: d.int(),
+        PartOfSpeech: d.int(),
+        Words: d.strs(),
+        LexIds: d.ints(),
+        Markers: d.ints(),
+    }
+    count := d.count(5)
+    synset.Relationships = make([]RelationshipEdge, count)
+    for i, _ := range synset.Relationships {
+        synset.Relationships[i] = RelationshipEdge { d.int(), d.int(), d.int(), d.int(), d.int() }
+    }
+    count = d.int()
+    if count >= 0 && count <= len(d.buf) {
+        synset.Frames = make([]VerbFrame, count)
+        for i, _ := range synset.Frames {
+            synset.Frames[i] = VerbFrame { d.int(), d.int() }
+        }
+    }
+    synset.Gloss = d.str()
+    synset.Definition = d.str()
+    synset.Examples = d.strs()
+    return synset, d.err == nil
+}
+
+func (s *snapshotReader) readSynset(pos int, synsetOffset int) (Synset, bool) {
+    table, exists := s.dataTables[pos]
+    if !exists {
+        return Synset{}, false
+    }
+    i := table.search(func(d *snapshotDecoder) bool { return d.int() >= synsetOffset })
+    synset, ok := decodeSnapshotSynset(table.record(i))
+    if !ok || synset.SynsetOffset != synsetOffset {
+        return Synset{}, false
+    }
+    return synset, true
+}
+
+func (s *snapshotReader) eachSynset(pos int, fn func(synset Synset) bool) {
+    table, exists := s.dataTables[pos]
+    if !exists {
+        return
+    }
+    for i := 0; i < table.count; i++ {
+        synset, ok := decodeSnapshotSynset(table.record(i))
+        if ok && !fn(synset) {
+            return
+        }
+    }
+}
+
+// senses: the fields of the sense key, synset offset, sense number, tag
+// count, syntactic marker, orthography
+
+func (wn *WN) snapshotSenses() ([]byte, error) {
+    senses := []SenseIndexEntry{}
+    for sense := range wn.IterSenses() {
+        senses = append(senses, *sense)
+    }
+    // keeping the order of the senses of each lemma
+    sort.SliceStable(senses, func(i, j int) bool { return senses[i].Lemma < senses[j].Lemma })
+    table := &snapshotTableWriter{}
+    for _, sense := range senses {
+        e := &snapshotEncoder{}
+        e.senseKey(sense.SenseKey)
+        e.int(sense.SynsetOffset)
+        e.int(sense.SenseNumber)
+        e.int(sense.TagCount)
+        e.int(sense.SyntacticMarker)
+        e.str(sense.Orthography)
+        table.add(e.buf)
+    }
+    return table.bytes()
+}
+
+func decodeSnapshotSense(wn *WN, d *snapshotDecoder) (SenseIndexEntry, bool) {
+    key := d.senseKey()
+    sense := SenseIndexEntry {
+        Lemma: key.Lemma,
+        PartOfSpeech: key.PartOfSpeech,
+        LexographerFilenum: key.LexographerFilenum,
+        LexId: key.LexId,
+        HeadWord: key.HeadWord,
+        HeadId: key.HeadId,
+        SynsetOffset: d.int(),
+        SenseNumber: d.int(),
+        TagCount: d.int(),
+        SenseKey: key,
+        SyntacticMarker: d.int(),
+        Orthography: d.str(),
+    }
+    if d.err != nil {
+        return SenseIndexEntry{}, false
+    }
+    if wn != nil {
+        sense.synsetPtr = wn.GetSynset(sense.PartOfSpeech, sense.SynsetOffset)
+    }
+    return sense, true
+}
+
+func (s *snapshotReader) readSenses(wn *WN, lemma string) []SenseIndexEntry {
+    ret := []SenseIndexEntry{}
+    i := s.senseTable.search(func(d *snapshotDecoder) bool { return d.str() >= lemma })
+    for ; i < s.senseTable.count; i++ {
+        sense, ok := decodeSnapshotSense(wn, s.senseTable.record(i))
+        if !ok || sense.Lemma != lemma {
+            break
+        }
+        ret = append(ret, sense)
+    }
+    return ret
+}
+
+func (s *snapshotReader) eachSense(wn *WN, fn func(entry SenseIndexEntry) bool) {
+    for i := 0; i < s.senseTable.count; i++ {
+        sense, ok := decodeSnapshotSense(wn, s.senseTable.record(i))
+        if ok && !fn(sense) {
+            return
+        }
+    }
+}
+
+// tagged glosses: pos, offset, definition tokens, examples. A token is its
+// text, lemma, tag and sense keys.
+
+func (wn *WN) snapshotGlossTags() ([]byte, error) {
+    keys := []synsetKey{}
+    glosses := map[synsetKey]*TaggedGloss{}
+    wn.eachTaggedGloss(func(k synsetKey, gloss *TaggedGloss) {
+        keys = append(keys, k)
+        glosses[k] = gloss
+    })
+    sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+    table := &snapshotTableWriter{}
+    for _, k := range keys {
+        e := &snapshotEncoder{}
+        e.int(k.pos)
+        e.int(k.offset)
+        e.glossTokens(glosses[k].Definition)
+        e.int(len(glosses[k].Examples))
+        for _, example := range glosses[k].Examples {
+            e.glossTokens(example)
+        }
+        table.add(e.buf)
+    }
+    return table.bytes()
+}
+
+func decodeSnapshotGlossTag(d *snapshotDecoder) (synsetKey, *TaggedGloss, bool) {
+    k := synsetKey { d.int(), d.int() }
+    gloss := &TaggedGloss { Definition: d.glossTokens() }
+    count := d.count(1)
+    gloss.Examples = make([][]GlossToken, count)
+    for i, _ := range gloss.Examples {
+        gloss.Examples[i] = d.glossTokens()
+    }
+    return k, gloss, d.err == nil
+}
+
+// Calls fn with each tagged gloss, from LoadGlossTags or the snapshot.
+func (wn *WN) eachTaggedGloss(fn func(k synsetKey, gloss *TaggedGloss)) {
+    if wn.taggedGlosses == nil && wn.snapshot != nil {
+        table := wn.snapshot.glossTagTable
+        for i := 0; i < table.count; i++ {
+            k, gloss, ok := decodeSnapshotGlossTag(table.record(i))
+            if ok {
+                fn(k, gloss)
+            }
+        }
+        return
+    }
+    for k, gloss := range wn.taggedGlosses {
+        fn(k, gloss)
+    }
+}
+
+func (s *snapshotReader) taggedGloss(k synsetKey) *TaggedGloss {
+    table := s.glossTagTable
+    i := table.search(func(d *snapshotDecoder) bool { return !(synsetKey { d.int(), d.int() }).less(k) })
+    found, gloss, ok := decodeSnapshotGlossTag(table.record(i))
+    if !ok || found != k {
+        return nil
+    }
+    return gloss
+}
+
+// ILI ids: pos, offset and id by synset, and id, pos (not normalized) and
+// offset by id
+
+func (wn *WN) snapshotILI() ([]byte, []byte, error) {
+    ids := []SynsetID{}
+    ilis := map[SynsetID]string{}
+    wn.eachILI(func(ili string, id SynsetID) {
+        ids = append(ids, id)
+        ilis[id] = ili
+    })
+    sort.Slice(ids, func(i, j int) bool { return ids[i].key().less(ids[j].key()) })
+    synsetTable := &snapshotTableWriter{}
+    for _, id := range ids {
+        e := &snapshotEncoder{}
+        e.int(id.key().pos)
+        e.int(id.key().offset)
+        e.str(ilis[id])
+        synsetTable.add(e.buf)
+    }
+    sort.Slice(ids, func(i, j int) bool { return ilis[ids[i]] < ilis[ids[j]] })
+    idTable := &snapshotTableWriter{}
+    for _, id := range ids {
+        e := &snapshotEncoder{}
+        e.str(ilis[id])
+        e.int(id.PartOfSpeech)
+        e.int(id.SynsetOffset)
+        idTable.add(e.buf)
+    }
+    synsetContent, err := synsetTable.bytes()
+    if err != nil {
+        return nil, nil, err
+    }
+    idContent, err := idTable.bytes()
+    return synsetContent, idContent, err
+}
+
+// Calls fn with each ILI id, from LoadILIMapping or the snapshot.
+func (wn *WN) eachILI(fn func(ili string, id SynsetID)) {
+    if wn.iliToSynset == nil && wn.snapshot != nil {
+        table := wn.snapshot.iliTable
+        for i := 0; i < table.count; i++ {
+            d := table.record(i)
+            ili, id := d.str(), SynsetID { d.int(), d.int() }
+            if d.err == nil {
+                fn(ili, id)
+            }
+        }
+        return
+    }
+    for ili, id := range wn.iliToSynset {
+        fn(ili, id)
+    }
+}
+
+func (s *snapshotReader) synsetByILI(ili string) (SynsetID, bool) {
+    table := s.iliTable
+    i := table.search(func(d *snapshotDecoder) bool { return d.str() >= ili })
+    d := table.record(i)
+    found, id := d.str(), SynsetID { d.int(), d.int() }
+    return id, d.err == nil && found == ili
+}
+
+func (s *snapshotReader) ili(k synsetKey) string {
+    table := s.iliSynsetTable
+    i := table.search(func(d *snapshotDecoder) bool { return !(synsetKey { d.int(), d.int() }).less(k) })
+    d := table.record(i)
+    found, ili := synsetKey { d.int(), d.int() }, d.str()
+    if d.err != nil || found != k {
+        return ""
+    }
+    return ili
+}
+
+// exceptions: the number of parts of speech, (-1 if InitMorphData wasn't
+// called) then for each the number of inflected forms, and each form with
+// its base forms
+
+func (wn *WN) snapshotExceptions() []byte {
+    e := &snapshotEncoder{}
+    if wn.exceptions == nil {
+        e.int(-1)
+        return e.buf
+    }
+    e.int(len(wn.exceptions))
+    for _, posExceptions := range wn.exceptions {
+        forms := make([]string, 0, len(posExceptions))
+        for form, _ := range posExceptions {
+            forms = append(forms, form)
+        }
+        sort.Strings(forms)
+        e.int(len(forms))
+        for _, form := range forms {
+            e.str(form)
+            e.strs(posExceptions[form])
+        }
+    }
+    return e.buf
+}
+
+func (wn *WN) readSnapshotExceptions(content []byte) error {
+    d := &snapshotDecoder { buf: content }
+    count := d.int()
+    if d.err != nil || count < 0 {
+        return d.err
+    }
+    if count > len(d.buf) {
+        return fmt.Errorf("%w: malformed exceptions", ErrSnapshotCorrupt)
+    }
+    exceptions := make([]map[string][]string, count)
+    for posIndex, _ := range exceptions {
+        exceptions[posIndex] = map[string][]string{}
+        forms := d.count(1)
+        for i := 0; i < forms && d.err == nil; i++ {
+            form := d.str()
+            exceptions[posIndex][form] = d.strs()
+        }
+    }
+    if d.err != nil {
+        return d.err
+    }
+    wn.setExceptions(exceptions)
+    return nil
+}
+
+// frames: the frame strings, then the sentences and the sentence index,
+// each -1 if they weren't loaded
+
+func (wn *WN) snapshotVerbFrames() []byte {
+    e := &snapshotEncoder{}
+    e.strs(wn.verbFrameStrings)
+    if wn.verbSentences == nil {
+        e.int(-1)
+    } else {
+        numbers := make([]int, 0, len(wn.verbSentences))
+        for number, _ := range wn.verbSentences {
+            numbers = append(numbers, number)
+        }
+        sort.Ints(numbers)
+        e.int(len(numbers))
+        for _, number := range numbers {
+            e.int(number)
+            e.str(wn.verbSentences[number])
+        }
+    }
+    if wn.verbSentenceIndex == nil {
+        e.int(-1)
+    } else {
+        keys := make([]string, 0, len(wn.verbSentenceIndex))
+        for k, _ := range wn.verbSentenceIndex {
+            keys = append(keys, k)
+        }
+        sort.Strings(keys)
+        e.int(len(keys))
+        for _, k := range keys {
+            e.str(k)
+            e.ints(wn.verbSentenceIndex[k])
+        }
+    }
+    return e.buf
+}
+
+func (wn *WN) readSnapshotVerbFrames(content []byte) error {
+    d := &snapshotDecoder { buf: content }
+    wn.verbFrameStrings = d.strs()
+    if len(wn.verbFrameStrings) == 0 {
+        wn.verbFrameStrings = VERB_FRAME_STRINGS
+    }
+    if count := d.int(); count >= 0 {
+        wn.verbSentences = map[int]string{}
+        for i := 0; i < count && d.err == nil; i++ {
+            number := d.int()
+            wn.verbSentences[number] = d.str()
+        }
+    }
+    if count := d.int(); count >= 0 {
+        wn.verbSentenceIndex = map[string][]int{}
+        for i := 0; i < count && d.err == nil; i++ {
+            k := d.str()
+            wn.verbSentenceIndex[k] = d.ints()
+        }
+    }
+    return d.err
+}
+
+// Builds a table of records, which must be added in order.
+type snapshotTableWriter struct {
+    offsets []uint32
+    records []byte
+}
+
+func (t *snapshotTableWriter) add(record []byte) {
+    t.offsets = append(t.offsets, uint32(len(t.records)))
+    t.records = append(t.records, record...)
+}
+
+func (t *snapshotTableWriter) bytes() ([]byte, error) {
+    if int64(len(t.records)) > int64(^uint32(0)) {
+        return nil, fmt.Errorf("%d bytes of records is too many", len(t.records))
+    }
+    ret := make([]byte, 4 * (len(t.offsets) + 2), 4 * (len(t.offsets) + 2) + len(t.records))
+    binary.BigEndian.PutUint32(ret, uint32(len(t.offsets)))
+    for i, offset := range t.offsets {
+        binary.BigEndian.PutUint32(ret[4 * (i + 1):], offset)
+    }
+    binary.BigEndian.PutUint32(ret[4 * (len(t.offsets) + 1):], uint32(len(t.records)))
+    return append(ret, t.records...), nil
+}
+
+// A table of a snapshot, read in place.
+type snapshotTable struct {
+    r io.ReaderAt
+    offset int64            // of the offset table
+    recordsOffset int64     // of the first record
+    size int64              // of the records
+    count int
+}
+
+func openSnapshotTable(r io.ReaderAt, sections map[string]snapshotSection, name string) (*snapshotTable, error) {
+    section, exists := sections[name]
+    if !exists {
+        return nil, fmt.Errorf("%w: no %s section", ErrSnapshotCorrupt, name)
+    }
+    countBytes := make([]byte, 4)
+    if section.size < 4 {
+        return nil, fmt.Errorf("%w: truncated %s section", ErrSnapshotCorrupt, name)
+    }
+    if _, err := r.ReadAt(countBytes, section.offset); err != nil {
+        return nil, err
+    }
+    count := int64(binary.BigEndian.Uint32(countBytes))
+    offsetsSize := 4 * (count + 1)
+    if 4 + offsetsSize > section.size {
+        return nil, fmt.Errorf("%w: truncated %s section", ErrSnapshotCorrupt, name)
+    }
+    return &snapshotTable {
+        r: r,
+        offset: section.offset + 4,
+        recordsOffset: section.offset + 4 + offsetsSize,
+        size: section.size - 4 - offsetsSize,
+        count: int(count),
+    }, nil
+}
+
+// Returns a decoder of record i. Its err is set if there is no such record
+// or it can't be read.
+func (t *snapshotTable) record(i int) *snapshotDecoder {
+    if i < 0 || i >= t.count {
+        return &snapshotDecoder { err: fmt.Errorf("%w: no record %d", ErrSnapshotCorrupt, i) }
+    }
+    bounds := make([]byte, 8)
+    if _, err := t.r.ReadAt(bounds, t.offset + 4 * int64(i)); err != nil {
+        return &snapshotDecoder { err: err }
+    }
+    start := int64(binary.BigEndian.Uint32(bounds))
+    end := int64(binary.BigEndian.Uint32(bounds[4:]))
+    if start > end || end > t.size {
+        return &snapshotDecoder { err: fmt.Errorf("%w: record %d is out of bounds", ErrSnapshotCorrupt, i) }
+    }
+    buf := make([]byte, end - start)
+    if _, err := t.r.ReadAt(buf, t.recordsOffset + start); err != nil {
+        return &snapshotDecoder { err: err }
+    }
+    return &snapshotDecoder { buf: buf }
+}
+
+// Returns the index of the first record for which atOrAfter is true, given
+// a decoder of the record, or t.count if there is none. (see sort.Search)
+func (t *snapshotTable) search(atOrAfter func(d *snapshotDecoder) bool) int {
+    return sort.Search(t.count, func(i int) bool {
+        d := t.record(i)
+        return atOrAfter(d) || d.err != nil
+    })
+}
+
+// Encodes a record of a snapshot.
+type snapshotEncoder struct {
+    buf []byte
+}
+
+func (e *snapshotEncoder) int(x int) {
+    var varint [binary.MaxVarintLen64]byte
+    n := binary.PutVarint(varint[:], int64(x))
+    e.buf = append(e.buf, varint[:n]...)
+}
+
+func (e *snapshotEncoder) uint64(x uint64) {
+    var fixed [8]byte
+    binary.BigEndian.PutUint64(fixed[:], x)
+    e.buf = append(e.buf, fixed[:]...)
+}
+
+func (e *snapshotEncoder) str(s string) {
+    e.int(len(s))
+    e.buf = append(e.buf, s...)
+}
+
+func (e *snapshotEncoder) ints(xs []int) {
+    e.int(len(xs))
+    for _, x := range xs {
+        e.int(x)
+    }
+}
+
+func (e *snapshotEncoder) strs(ss []string) {
+    e.int(len(ss))
+    for _, s := range ss {
+        e.str(s)
+    }
+}
+
+func (e *snapshotEncoder) senseKey(key SenseKey) {
+    e.str(key.Lemma)
+    e.int(key.PartOfSpeech)
+    e.int(key.LexographerFilenum)
+    e.int(key.LexId)
+    e.str(key.HeadWord)
+    e.int(key.HeadId)
+}
+
+func (e *snapshotEncoder) glossTokens(tokens []GlossToken) {
+    e.int(len(tokens))
+    for _, token := range tokens {
+        e.str(token.Text)
+        e.str(token.Lemma)
+        e.str(token.Tag)
+        e.int(len(token.SenseKeys))
+        for _, key := range token.SenseKeys {
+            e.senseKey(key)
+        }
+    }
+}
+
+// Decodes a record of a snapshot. Like fieldReader, it keeps the first
+// error in err, and returns zero values after it.
+type snapshotDecoder struct {
+    buf []byte
+    err error
+}
+
+func (d *snapshotDecoder) int() int {
+    if d.err != nil {
+        return 0
+    }
+    x, n := binary.Varint(d.buf)
+    if n <= 0 {
+        d.err = fmt.Errorf("%w: malformed record", ErrSnapshotCorrupt)
+        return 0
+    }
+    d.buf = d.buf[n:]
+    return int(x)
+}
+
+func (d *snapshotDecoder) uint64() uint64 {
+    if d.err != nil {
+        return 0
+    }
+    if len(d.buf) < 8 {
+        d.err = fmt.Errorf("%w: malformed record", ErrSnapshotCorrupt)
+        return 0
+    }
+    x := binary.BigEndian.Uint64(d.buf)
+    d.buf = d.buf[8:]
+    return x
+}
+
+// Reads the count of a list whose items take at least itemSize bytes, so
+// a corrupt count can't allocate more than the record holds.
+func (d *snapshotDecoder) count(itemSize int) int {
+    count := d.int()
+    if d.err == nil && (count < 0 || count * itemSize > len(d.buf)) {
+        d.err = fmt.Errorf("%w: malformed record", ErrSnapshotCorrupt)
+    }
+    if d.err != nil {
+        return 0
+    }
+    return count
+}
+
+func (d *snapshotDecoder) str() string {
+    n := d.count(1)
+    if d.err != nil {
+        return ""
+    }
+    s := string(d.buf[:n])
+    d.buf = d.buf[n:]
+    return s
+}
+
+func (d *snapshotDecoder) ints() []int {
+    ret := make([]int, d.count(1))
+    for i, _ := range ret {
+        ret[i] = d.int()
+    }
+    return ret
+}
+
+func (d *snapshotDecoder) strs() []string {
+    ret := make([]string, d.count(1))
+    for i, _ := range ret {
+        ret[i] = d.str()
+    }
+    return ret
+}
+
+func (d *snapshotDecoder) senseKey() SenseKey {
+    return SenseKey { d.str(), d.int(), d.int(), d.int(), d.str(), d.int() }
+}
+
+func (d *snapshotDecoder) glossTokens() []GlossToken {
+    ret := make([]GlossToken, d.count(4))
+    for i, _ := range ret {
+        ret[i] = GlossToken { Text: d.str(), Lemma: d.str(), Tag: d.str() }
+        keys := d.count(6)
+        if keys > 0 {
+            ret[i].SenseKeys = make([]SenseKey, keys)
+            for j, _ := range ret[i].SenseKeys {
+                ret[i].SenseKeys[j] = d.senseKey()
+            }
+        }
+    }
+    return ret
+}
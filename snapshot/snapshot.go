@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ozlo/gown"
+)
+
+// Builds a database snapshot for gown.LoadSnapshot from a dictionary
+// directory.
+func main() {
+	dictDir := flag.String("dict", "", "WordNet dictionary directory (default: found by GetWordNetDictDir)")
+	output := flag.String("o", "wordnet.gown", "snapshot file to write")
+	glossTagDir := flag.String("glosstags", "", "gloss tag directory to include (optional)")
+	iliFilename := flag.String("ili", "", "ILI mapping file to include (optional)")
+	flag.Parse()
+
+	if *dictDir == "" {
+		var err error
+		*dictDir, err = gown.GetWordNetDictDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	wn, err := gown.LoadWordNet(*dictDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't load WordNet from %v: %v\n", *dictDir, err)
+		os.Exit(1)
+	}
+	err = wn.InitMorphData(*dictDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't load morph data from %v: %v\n", *dictDir, err)
+		os.Exit(1)
+	}
+	if *glossTagDir != "" {
+		err = wn.LoadGlossTags(*glossTagDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "can't load gloss tags from %v: %v\n", *glossTagDir, err)
+			os.Exit(1)
+		}
+	}
+	if *iliFilename != "" {
+		err = wn.LoadILIMapping(*iliFilename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "can't load ILI mapping from %v: %v\n", *iliFilename, err)
+			os.Exit(1)
+		}
+	}
+
+	err = wn.WriteSnapshotFile(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't write %s: %v\n", *output, err)
+		os.Exit(1)
+	}
+	fmt.Printf("wrote %s\n", *output)
+}
@@ -0,0 +1,152 @@
+package gown
+
+import (
+    "bytes"
+    "errors"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+func TestSnapshot(t *testing.T) {
+    forEachLoadMode(t, LoadOptions{}, func(t *testing.T, wn *WN) {
+        dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+        wn.taggedGlosses = map[synsetKey]*TaggedGloss {
+            getSynsetKey(dog): &TaggedGloss {
+                []GlossToken { { "member", "member", "man", []SenseKey { { "member", POS_NOUN, 14, 0, "", 0 } } } },
+                [][]GlossToken { { { "dog", "dog", "auto", nil } } },
+            },
+        }
+        wn.iliToSynset = map[string]SynsetID { "i46360": dog.ID() }
+        wn.synsetToIli = map[synsetKey]string { getSynsetKey(dog): "i46360" }
+
+        snapshotFilename := filepath.Join(t.TempDir(), "wordnet.gown")
+        err := wn.WriteSnapshotFile(snapshotFilename)
+        if err != nil {
+            t.Fatalf("failed to write snapshot: %v", err)
+        }
+        snapshotWn, err := LoadSnapshot(snapshotFilename)
+        if err != nil {
+            t.Fatalf("failed to load snapshot: %v", err)
+        }
+        defer snapshotWn.Close()
+
+        synsets := map[synsetKey]*Synset{}
+        for synset := range wn.Iter() {
+            synsets[getSynsetKey(synset)] = synset
+        }
+        count := 0
+        for synset := range snapshotWn.Iter() {
+            count++
+            if !reflect.DeepEqual(synset, synsets[getSynsetKey(synset)]) {
+                t.Errorf("synset %v differs in the snapshot: %v", synsets[getSynsetKey(synset)], synset)
+            }
+        }
+        if count != len(synsets) {
+            t.Errorf("expected %d synsets in the snapshot, got %d", len(synsets), count)
+        }
+
+        if !reflect.DeepEqual(wn.LookupWithPartOfSpeech("computer", POS_NOUN), snapshotWn.LookupWithPartOfSpeech("computer", POS_NOUN)) {
+            t.Errorf("\"computer\" differs in the snapshot")
+        }
+        senses := snapshotWn.Lookup("dog")
+        if len(senses) != len(wn.Lookup("dog")) || len(senses) == 0 {
+            t.Fatalf("unexpected senses of \"dog\" in the snapshot: %v", senses)
+        }
+        synset := senses[0].GetSynsetPtr()
+        if synset == nil || synset.Gloss != wn.GetSynset(senses[0].PartOfSpeech, senses[0].SynsetOffset).Gloss {
+            t.Errorf("sense of \"dog\" isn't linked to its synset in the snapshot")
+        }
+        if snapshotWn.Morph("swam", POS_VERB) != wn.Morph("swam", POS_VERB) {
+            t.Errorf("morph exceptions differ in the snapshot")
+        }
+        if !reflect.DeepEqual(wn.PrefixSearch("do", LemmaSearchOptions{}), snapshotWn.PrefixSearch("do", LemmaSearchOptions{})) {
+            t.Errorf("prefix search differs in the snapshot")
+        }
+        if !reflect.DeepEqual(wn.TaggedGloss(dog), snapshotWn.TaggedGloss(dog)) {
+            t.Errorf("tagged gloss of dog differs in the snapshot: %v", snapshotWn.TaggedGloss(dog))
+        }
+        if snapshotWn.TaggedGloss(synset) != nil && synset.ID() != dog.ID() {
+            t.Errorf("unexpected tagged gloss of %v", synset)
+        }
+        if snapshotWn.ILI(dog) != "i46360" || snapshotWn.SynsetByILI("i46360").ID() != dog.ID() {
+            t.Errorf("ILI mapping differs in the snapshot")
+        }
+        if snapshotWn.SynsetByILI("i1") != nil {
+            t.Errorf("unexpected synset for i1")
+        }
+    })
+}
+
+func TestReadSnapshotInPlace(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    buf := &bytes.Buffer{}
+    if err := wn.WriteSnapshot(buf); err != nil {
+        t.Fatalf("failed to write snapshot: %v", err)
+    }
+    snapshotWn, err := ReadSnapshot(bytes.NewReader(buf.Bytes()), int64(buf.Len()), LoadOptions { InverseRelationships: true })
+    if err != nil {
+        t.Fatalf("failed to read snapshot: %v", err)
+    }
+    dog := snapshotWn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1)
+    if dog == nil || dog.GetSynsetPtr() == nil {
+        t.Fatalf("can't find dog in the snapshot")
+    }
+    if len(snapshotWn.PosIndicies) != 0 {
+        t.Errorf("expected the index to be read in place")
+    }
+    // the inverse of "snore" entailing "sleep" is only in the loaded copy
+    sleep := snapshotWn.LookupWithPartOfSpeechAndSense("sleep", POS_VERB, 1).GetSynsetPtr()
+    snore := snapshotWn.LookupWithPartOfSpeechAndSense("snore", POS_VERB, 1).GetSynsetPtr()
+    if sleep == nil || snore == nil {
+        t.Fatalf("can't find \"sleep\" or \"snore\" in the snapshot")
+    }
+    if countRelationships(sleep, ENTAILED_BY_RELATIONSHIP, snore) != 1 {
+        t.Errorf("expected \"sleep\" to be entailed by \"snore\" in the snapshot")
+    }
+    if countRelationships(wn.GetSynset(POS_VERB, sleep.SynsetOffset), ENTAILED_BY_RELATIONSHIP, snore) != 0 {
+        t.Errorf("expected the inverse relationships to be added after reading the snapshot")
+    }
+}
+
+type failingWriter struct {
+    remaining int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+    if len(p) > w.remaining {
+        return 0, errors.New("disk full")
+    }
+    w.remaining -= len(p)
+    return len(p), nil
+}
+
+func TestReadSnapshotErrors(t *testing.T) {
+    _, err := ReadSnapshot(bytes.NewReader([]byte("index.noun")), 10, LoadOptions{})
+    if !errors.Is(err, ErrNotSnapshot) {
+        t.Errorf("expected ErrNotSnapshot, got %v", err)
+    }
+    header := SNAPSHOT_MAGIC + "\xff\xff\xff\xff\x00\x00\x00\x00"
+    _, err = ReadSnapshot(bytes.NewReader([]byte(header)), int64(len(header)), LoadOptions{})
+    if !errors.Is(err, ErrSnapshotVersion) {
+        t.Errorf("expected ErrSnapshotVersion, got %v", err)
+    }
+
+    wn := loadTestWordNet(t, LoadOptions{})
+    buf := &bytes.Buffer{}
+    if err := wn.WriteSnapshot(buf); err != nil {
+        t.Fatalf("failed to write snapshot: %v", err)
+    }
+    truncated := buf.Bytes()[:buf.Len() / 2]
+    _, err = ReadSnapshot(bytes.NewReader(truncated), int64(len(truncated)), LoadOptions{})
+    if !errors.Is(err, ErrSnapshotCorrupt) {
+        t.Errorf("expected ErrSnapshotCorrupt, got %v", err)
+    }
+
+    // every write is checked, including the magic and version
+    for _, remaining := range []int { 0, len(SNAPSHOT_MAGIC), buf.Len() - 1 } {
+        if wn.WriteSnapshot(&failingWriter { remaining }) == nil {
+            t.Errorf("expected an error writing a snapshot after %d bytes", remaining)
+        }
+    }
+}
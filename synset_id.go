@@ -0,0 +1,196 @@
+package gown
+
+import (
+    "fmt"
+    "io"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+)
+
+/*
+Synsets can be identified in several ways:
+  - by offset and part of speech, e.g. "02084071-n". Offsets change between
+    WordNet versions.
+  - by NLTK style name, e.g. "dog.n.01": the first word of the synset, the
+    part of speech and the position of the synset among the senses of the
+    word.
+  - by Interlingual Index (ILI) id, e.g. "i46360", which is stable across
+    versions and wordnets. This needs a mapping file from the Collaborative
+    Interlingual Index (e.g. ili-map-pwn30.tab).
+*/
+
+type SynsetID struct {
+    PartOfSpeech int       // POS_NOUN, etc. Adjective satellites may be POS_ADJECTIVE or POS_ADJECTIVE_SATELLITE
+    SynsetOffset int
+}
+
+// Returns the id of a synset.
+func (s *Synset) ID() SynsetID {
+    return SynsetID { s.PartOfSpeech, s.SynsetOffset }
+}
+
+// Formats the id as the offset and part of speech, e.g. "02084071-n".
+func (id SynsetID) String() string {
+    return fmt.Sprintf("%08d-%s", id.SynsetOffset, posIdToOneCharPosTag(id.PartOfSpeech))
+}
+
+// Parses an id formatted by SynsetID.String, e.g. "02084071-n".
+func ParseSynsetID(s string) (SynsetID, error) {
+    dashIndex := strings.LastIndex(s, "-")
+    if dashIndex <= 0 {
+        return SynsetID{}, fmt.Errorf("malformed synset id %q", s)
+    }
+    offset, err := strconv.Atoi(s[:dashIndex])
+    pos := oneCharPosTagToPosId(s[dashIndex + 1:])
+    if err != nil || offset < 0 || pos == POS_UNSUPPORTED {
+        return SynsetID{}, fmt.Errorf("malformed synset id %q", s)
+    }
+    return SynsetID { pos, offset }, nil
+}
+
+func (id SynsetID) key() synsetKey {
+    return synsetKey { normalizePos(id.PartOfSpeech), id.SynsetOffset }
+}
+
+// Returns the synset with the id, or nil if there is none.
+func (wn *WN) GetSynsetByID(id SynsetID) *Synset {
+    return wn.GetSynset(id.PartOfSpeech, id.SynsetOffset)
+}
+
+// Returns the NLTK style name of a synset, e.g. "dog.n.01", or "" if its
+// first word isn't in the index.
+func (wn *WN) SynsetName(synset *Synset) string {
+    if len(synset.Words) == 0 {
+        return ""
+    }
+    lemma := strings.ToLower(synset.Words[0])
+    dataIndexEntry := wn.LookupWithPartOfSpeech(lemma, normalizePos(synset.PartOfSpeech))
+    if dataIndexEntry == nil {
+        return ""
+    }
+    for i, synsetOffset := range dataIndexEntry.SynsetOffsets {
+        if synsetOffset == synset.SynsetOffset {
+            return fmt.Sprintf("%s.%s.%02d", writeStoredLemma(lemma), posIdToOneCharPosTag(synset.PartOfSpeech), i + 1)
+        }
+    }
+    return ""
+}
+
+// Returns the synset with an NLTK style name (e.g. "dog.n.01"), or nil if
+// there is none. Any word of the synset may be used in the name.
+func (wn *WN) SynsetByName(name string) *Synset {
+    fields := strings.Split(name, ".")
+    if len(fields) < 3 {
+        return nil
+    }
+    lemma := readStoredLemma(strings.Join(fields[:len(fields) - 2], "."))
+    pos := oneCharPosTagToPosId(fields[len(fields) - 2])
+    senseNumber, err := strconv.Atoi(fields[len(fields) - 1])
+    if err != nil || pos == POS_UNSUPPORTED {
+        return nil
+    }
+    dataIndexEntry := wn.LookupWithPartOfSpeech(lemma, normalizePos(pos))
+    if dataIndexEntry == nil || senseNumber < 1 || senseNumber > len(dataIndexEntry.SynsetOffsets) {
+        return nil
+    }
+    synset := wn.GetSynset(pos, dataIndexEntry.SynsetOffsets[senseNumber - 1])
+    if synset == nil || (pos == POS_ADJECTIVE_SATELLITE) != (synset.PartOfSpeech == POS_ADJECTIVE_SATELLITE) {
+        return nil
+    }
+    return synset
+}
+
+// Reads a mapping of ILI ids to synsets, such as ili-map-pwn30.tab from
+// the Collaborative Interlingual Index. The format is:
+// ili_id  synset_id
+// e.g. "i46360	02084071-n". It must match the WordNet version loaded.
+func (wn *WN) LoadILIMapping(iliFilename string) error {
+    return wn.LoadILIMappingFS(os.DirFS(filepath.Dir(iliFilename)), filepath.Base(iliFilename))
+}
+
+// Like LoadILIMapping, but reads the file from fsys.
+func (wn *WN) LoadILIMappingFS(fsys fs.FS, iliFilename string) error {
+    infile, err := openDictFile(fsys, iliFilename)
+    if err != nil {
+        return fmt.Errorf("can't open %s: %v", iliFilename, err)
+    }
+    defer infile.Close()
+
+    iliToSynset := map[string]SynsetID{}
+    synsetToIli := map[synsetKey]string{}
+    lr := newLineReader(infile, iliFilename)
+    for {
+        line, err := lr.next()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return err
+        }
+        f := lr.fields(line)
+        if !f.more() {
+            continue
+        }
+        ili := f.str("ili_id")
+        synsetIdField := f.str("synset_id")
+        if f.err != nil {
+            return f.err
+        }
+        id, err := ParseSynsetID(synsetIdField)
+        if err != nil {
+            return lr.fieldError("synset_id", synsetIdField)
+        }
+        iliToSynset[ili] = id
+        synsetToIli[id.key()] = ili
+    }
+
+    wn.iliToSynset = iliToSynset
+    wn.synsetToIli = synsetToIli
+    return nil
+}
+
+// Returns the synset with the ILI id (e.g. "i46360"), or nil if there is
+// none or no mapping was loaded.
+func (wn *WN) SynsetByILI(ili string) *Synset {
+    if wn.iliToSynset == nil && wn.snapshot != nil {
+        id, exists := wn.snapshot.synsetByILI(ili)
+        if !exists {
+            return nil
+        }
+        return wn.GetSynsetByID(id)
+    }
+    id, exists := wn.iliToSynset[ili]
+    if !exists {
+        return nil
+    }
+    return wn.GetSynsetByID(id)
+}
+
+// Returns the ILI id of a synset, or "" if it has none or no mapping was
+// loaded.
+func (wn *WN) ILI(synset *Synset) string {
+    if wn.synsetToIli == nil && wn.snapshot != nil {
+        return wn.snapshot.ili(getSynsetKey(synset))
+    }
+    return wn.synsetToIli[getSynsetKey(synset)]
+}
+
+func posIdToOneCharPosTag(pos int) string {
+    switch pos {
+    case POS_NOUN:
+        return "n"
+    case POS_VERB:
+        return "v"
+    case POS_ADJECTIVE:
+        return "a"
+    case POS_ADVERB:
+        return "r"
+    case POS_ADJECTIVE_SATELLITE:
+        return "s"
+    default:
+        return "?"
+    }
+}
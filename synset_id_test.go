@@ -0,0 +1,82 @@
+package gown
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestParseSynsetID(t *testing.T) {
+    id, err := ParseSynsetID("02084071-n")
+    if err != nil || id != (SynsetID { POS_NOUN, 2084071 }) {
+        t.Errorf("unexpected id %v (%v)", id, err)
+    }
+    if id.String() != "02084071-n" {
+        t.Errorf("unexpected string %s", id.String())
+    }
+    id, err = ParseSynsetID("01123148-s")
+    if err != nil || id != (SynsetID { POS_ADJECTIVE_SATELLITE, 1123148 }) {
+        t.Errorf("unexpected id %v (%v)", id, err)
+    }
+    for _, malformed := range []string { "", "02084071", "-n", "02084071-x", "dog-n" } {
+        if _, err := ParseSynsetID(malformed); err == nil {
+            t.Errorf("expected an error parsing %q", malformed)
+        }
+    }
+}
+
+func TestSynsetName(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+    if wn.GetSynsetByID(dog.ID()).ID() != dog.ID() {
+        t.Errorf("expected to find dog by its id %s", dog.ID())
+    }
+    if name := wn.SynsetName(dog); name != "dog.n.01" {
+        t.Errorf("expected dog.n.01, got %s", name)
+    }
+    if wn.SynsetByName("dog.n.01").ID() != dog.ID() {
+        t.Errorf("expected dog.n.01 to be dog")
+    }
+    // any word of the synset can name it
+    if wn.SynsetByName("domestic_dog.n.01").ID() != dog.ID() {
+        t.Errorf("expected domestic_dog.n.01 to be dog")
+    }
+    for _, name := range []string { "dog.n.00", "dog.n.1000", "dog.x.01", "dog.n", "notaword.n.01" } {
+        if wn.SynsetByName(name) != nil {
+            t.Errorf("expected no synset named %s", name)
+        }
+    }
+}
+
+func TestILIMapping(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+
+    if wn.SynsetByILI("i46360") != nil || wn.ILI(dog) != "" {
+        t.Errorf("expected no ILI ids without a mapping")
+    }
+
+    iliFilename := filepath.Join(t.TempDir(), "ili-map.tab")
+    mapping := fmt.Sprintf("i46360\t%s\n", dog.ID())
+    if err := os.WriteFile(iliFilename, []byte(mapping), 0644); err != nil {
+        t.Fatal(err)
+    }
+    if err := wn.LoadILIMapping(iliFilename); err != nil {
+        t.Fatalf("can't load ILI mapping: %v", err)
+    }
+    if wn.SynsetByILI("i46360").ID() != dog.ID() {
+        t.Errorf("expected i46360 to be dog")
+    }
+    if wn.ILI(dog) != "i46360" {
+        t.Errorf("expected the ILI id of dog to be i46360, got %q", wn.ILI(dog))
+    }
+
+    if err := os.WriteFile(iliFilename, []byte("i46360\tnot-an-id\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+    if err := wn.LoadILIMapping(iliFilename); err == nil {
+        t.Errorf("expected an error loading a malformed mapping")
+    }
+}
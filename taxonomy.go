@@ -0,0 +1,140 @@
+package gown
+
+/*
+Measures of the position of synsets in the noun and verb taxonomies, the
+hierarchies formed by HYPERNYM_RELATIONSHIP and
+INSTANCE_HYPERNYM_RELATIONSHIP edges. They match the NLTK functions of the
+same names.
+*/
+
+// Returns the deepest hypernyms the two synsets have in common, which may
+// include either synset itself, ordered by name. (see SynsetName) The
+// depth of a synset is its MaxDepth.
+func (wn *WN) LowestCommonHypernyms(s1 *Synset, s2 *Synset) []*Synset {
+    ret := []*Synset{}
+    for _, k := range wn.lowestCommonHypernymKeys(s1, s2, false, false) {
+        synset := wn.GetSynset(k.pos, k.offset)
+        if synset != nil {
+            ret = append(ret, synset)
+        }
+    }
+    return ret
+}
+
+// Returns the length of the shortest hypernym path from the synset to a
+// unique beginner (e.g. "entity").
+func (wn *WN) MinDepth(synset *Synset) int {
+    return wn.keyMinDepth(getSynsetKey(synset), map[synsetKey]int{})
+}
+
+// Returns the length of the longest hypernym path from the synset to a
+// unique beginner (e.g. "entity").
+func (wn *WN) MaxDepth(synset *Synset) int {
+    return wn.keyMaxDepth(getSynsetKey(synset), map[synsetKey]int{})
+}
+
+// Returns the number of edges on the shortest path between the synsets
+// through a common hypernym. With simulateRoot, the synsets are connected
+// through a virtual root above the unique beginners if they have no common
+// hypernym. The second return value is false if there is no path.
+func (wn *WN) ShortestPathDistance(s1 *Synset, s2 *Synset, simulateRoot bool) (int, bool) {
+    if getSynsetKey(s1) == getSynsetKey(s2) {
+        return 0, true
+    }
+    distance := pathDistance(wn.hypernymDistances(s1, simulateRoot), wn.hypernymDistances(s2, simulateRoot))
+    return distance, distance >= 0
+}
+
+// Returns the shortest path between the synsets through a common hypernym:
+// the synsets on it, starting with s1 and ending with s2, and the edges
+// between them. edges[i] leads from synsets[i] to synsets[i + 1]. The edges
+// going up are hypernym edges of the synsets, the edges going down are
+// their inverses (see RELATIONSHIP_INVERSES). The third return value is
+// false if there is no path.
+func (wn *WN) ShortestPath(s1 *Synset, s2 *Synset) ([]*Synset, []RelationshipEdge, bool) {
+    steps1 := wn.hypernymSteps(s1)
+    steps2 := wn.hypernymSteps(s2)
+
+    best := -1
+    var subsumer synsetKey
+    for k, step1 := range steps1 {
+        step2, common := steps2[k]
+        if !common {
+            continue
+        }
+        distance := step1.distance + step2.distance
+        if best < 0 || distance < best ||
+            (distance == best && (k.pos < subsumer.pos || (k.pos == subsumer.pos && k.offset < subsumer.offset))) {
+            best = distance
+            subsumer = k
+        }
+    }
+    if best < 0 {
+        return nil, nil, false
+    }
+
+    // the chains of steps from the subsumer down to each synset
+    chain1 := stepChain(steps1, subsumer)
+    chain2 := stepChain(steps2, subsumer)
+
+    synsets := []*Synset{}
+    edges := []RelationshipEdge{}
+    // up from s1
+    for i := len(chain1) - 1; i > 0; i-- {
+        synsets = append(synsets, steps1[chain1[i]].synset)
+        edges = append(edges, steps1[chain1[i - 1]].edge)
+    }
+    synsets = append(synsets, steps1[subsumer].synset)
+    // down to s2
+    for i := 1; i < len(chain2); i++ {
+        synset := steps2[chain2[i]].synset
+        inverse, _ := invertEdge(synset, steps2[chain2[i - 1]].edge)
+        synsets = append(synsets, synset)
+        edges = append(edges, inverse)
+    }
+    return synsets, edges, true
+}
+
+// Returns the synsets from k down to the start of the walk.
+func stepChain(steps map[synsetKey]hypernymStep, k synsetKey) []synsetKey {
+    chain := []synsetKey { k }
+    for steps[k].distance > 0 {
+        k = steps[k].child
+        chain = append(chain, k)
+    }
+    return chain
+}
+
+// how a synset was reached walking up from another one
+type hypernymStep struct {
+    synset *Synset
+    distance int
+    child synsetKey        // the synset it was reached from. (not set for the start)
+    edge RelationshipEdge  // the hypernym edge from child to synset
+}
+
+// Walks up the hypernyms breadth first, recording the first step to each.
+func (wn *WN) hypernymSteps(s *Synset) map[synsetKey]hypernymStep {
+    steps := map[synsetKey]hypernymStep { getSynsetKey(s): hypernymStep { synset: s } }
+    queue := []synsetKey { getSynsetKey(s) }
+    for len(queue) > 0 {
+        current := steps[queue[0]]
+        queue = queue[1:]
+        for _, edge := range current.synset.Relationships {
+            if !containsInt(HYPERNYM_RELATIONSHIPS, edge.RelationshipType) {
+                continue
+            }
+            k := synsetKey { normalizePos(edge.PartOfSpeech), edge.SynsetOffset }
+            if _, seen := steps[k]; seen {
+                continue
+            }
+            hypernym := wn.GetSynset(edge.PartOfSpeech, edge.SynsetOffset)
+            if hypernym == nil {
+                continue
+            }
+            steps[k] = hypernymStep { hypernym, current.distance + 1, getSynsetKey(current.synset), edge }
+            queue = append(queue, k)
+        }
+    }
+    return steps
+}
@@ -0,0 +1,45 @@
+package gown
+
+import (
+    "testing"
+)
+
+func TestTaxonomy(t *testing.T) {
+    wn, _ := loadSystemWordNet(t)
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+    cat := wn.LookupWithPartOfSpeechAndSense("cat", POS_NOUN, 1).GetSynsetPtr()
+    carnivore := wn.LookupWithPartOfSpeechAndSense("carnivore", POS_NOUN, 1).GetSynsetPtr()
+
+    // reference values from NLTK
+    if wn.MinDepth(dog) != 8 || wn.MaxDepth(dog) != 13 {
+        t.Errorf("expected dog to have depths 8 and 13, got %d and %d", wn.MinDepth(dog), wn.MaxDepth(dog))
+    }
+    common := wn.LowestCommonHypernyms(dog, cat)
+    if len(common) != 1 || common[0].SynsetOffset != carnivore.SynsetOffset {
+        t.Errorf("expected carnivore as the lowest common hypernym of dog and cat, got %v", common)
+    }
+    distance, connected := wn.ShortestPathDistance(dog, cat, false)
+    if !connected || distance != 4 {
+        t.Errorf("expected a distance of 4 between dog and cat, got %d", distance)
+    }
+
+    synsets, edges, connected := wn.ShortestPath(dog, cat)
+    if !connected || len(synsets) != distance + 1 || len(edges) != distance {
+        t.Fatalf("unexpected path between dog and cat: %v %v", synsets, edges)
+    }
+    if synsets[0] != dog || synsets[len(synsets) - 1].SynsetOffset != cat.SynsetOffset || synsets[2].SynsetOffset != carnivore.SynsetOffset {
+        t.Errorf("unexpected path between dog and cat: %v", synsets)
+    }
+    for i, edge := range edges {
+        if edge.SynsetOffset != synsets[i + 1].SynsetOffset {
+            t.Errorf("edge %d doesn't lead to synset %d: %v", i, i + 1, edge)
+        }
+        expectedType := HYPERNYM_RELATIONSHIP
+        if i >= 2 {
+            expectedType = HYPONYM_RELATIONSHIP
+        }
+        if edge.RelationshipType != expectedType {
+            t.Errorf("edge %d is a %s", i, RELATIONSHIP_ID_TO_STRING[edge.RelationshipType])
+        }
+    }
+}
@@ -0,0 +1,155 @@
+package gown
+
+/*
+Traversals follow the edges of a chosen set of relationship types from a
+synset. (e.g. HYPERNYM_RELATIONSHIPS to walk up the taxonomy) All of them
+stop at cycles, so they're safe to use with any relationship type.
+*/
+
+// follow these to walk up the taxonomy
+var HYPERNYM_RELATIONSHIPS = []int { HYPERNYM_RELATIONSHIP, INSTANCE_HYPERNYM_RELATIONSHIP }
+
+// follow these to walk down the taxonomy. (verb troponyms are only present
+// after AddInverseRelationships)
+var HYPONYM_RELATIONSHIPS = []int { HYPONYM_RELATIONSHIP, INSTANCE_HYPONYM_RELATIONSHIP, TROPONYM_RELATIONSHIP }
+
+type SynsetTree struct {
+    Synset *Synset
+    Children []*SynsetTree
+}
+
+// Returns the synsets the synset points to with any of the relationship
+// types, in the order of its edges.
+func (wn *WN) Related(synset *Synset, relationshipTypes []int) []*Synset {
+    ret := []*Synset{}
+    seen := map[synsetKey]bool{}
+    for _, edge := range synset.Relationships {
+        if !containsInt(relationshipTypes, edge.RelationshipType) {
+            continue
+        }
+        k := synsetKey { normalizePos(edge.PartOfSpeech), edge.SynsetOffset }
+        if seen[k] {
+            continue
+        }
+        seen[k] = true
+        related := wn.GetSynset(edge.PartOfSpeech, edge.SynsetOffset)
+        if related != nil {
+            ret = append(ret, related)
+        }
+    }
+    return ret
+}
+
+// Walks breadth first from start along edges of the relationship types,
+// calling visit with each synset the first time it's reached and its
+// distance from start. start itself is visited first, at depth 0. If visit
+// returns false the synset's edges aren't followed. A negative maxDepth
+// means no limit.
+func (wn *WN) Walk(start *Synset, relationshipTypes []int, maxDepth int, visit func(synset *Synset, depth int) bool) {
+    if start == nil {
+        return
+    }
+    seen := map[synsetKey]bool { getSynsetKey(start): true }
+    queue := []*Synset { start }
+    depths := []int { 0 }
+    for len(queue) > 0 {
+        current, depth := queue[0], depths[0]
+        queue, depths = queue[1:], depths[1:]
+        if !visit(current, depth) || depth == maxDepth {
+            continue
+        }
+        for _, related := range wn.Related(current, relationshipTypes) {
+            k := getSynsetKey(related)
+            if seen[k] {
+                continue
+            }
+            seen[k] = true
+            queue = append(queue, related)
+            depths = append(depths, depth + 1)
+        }
+    }
+}
+
+// Returns every synset reachable from start along edges of the
+// relationship types, nearest first, not including start. (e.g. all the
+// hypernyms of a synset) A negative maxDepth means no limit.
+func (wn *WN) Closure(start *Synset, relationshipTypes []int, maxDepth int) []*Synset {
+    ret := []*Synset{}
+    wn.Walk(start, relationshipTypes, maxDepth, func(synset *Synset, depth int) bool {
+        if depth > 0 {
+            ret = append(ret, synset)
+        }
+        return true
+    })
+    return ret
+}
+
+// Returns every path from start along edges of the relationship types to a
+// synset without any, such as the paths from a synset to the unique
+// beginner "entity" with HYPERNYM_RELATIONSHIPS. Each path begins with the
+// root and ends with start.
+func (wn *WN) PathsToRoot(start *Synset, relationshipTypes []int) [][]*Synset {
+    if start == nil {
+        return [][]*Synset{}
+    }
+    return wn.pathsToRoot(start, relationshipTypes, map[synsetKey]bool{})
+}
+
+func (wn *WN) pathsToRoot(synset *Synset, relationshipTypes []int, onPath map[synsetKey]bool) [][]*Synset {
+    k := getSynsetKey(synset)
+    onPath[k] = true
+    defer delete(onPath, k)
+
+    ret := [][]*Synset{}
+    for _, related := range wn.Related(synset, relationshipTypes) {
+        if onPath[getSynsetKey(related)] {
+            // a cycle
+            continue
+        }
+        for _, path := range wn.pathsToRoot(related, relationshipTypes, onPath) {
+            ret = append(ret, append(path, synset))
+        }
+    }
+    if len(ret) == 0 {
+        ret = append(ret, []*Synset { synset })
+    }
+    return ret
+}
+
+// Returns the tree of synsets reachable from start along edges of the
+// relationship types. A synset reachable along several paths appears under
+// each of them, but a path stops before it would repeat a synset. A
+// negative maxDepth means no limit.
+func (wn *WN) Tree(start *Synset, relationshipTypes []int, maxDepth int) *SynsetTree {
+    if start == nil {
+        return nil
+    }
+    return wn.tree(start, relationshipTypes, maxDepth, map[synsetKey]bool{})
+}
+
+func (wn *WN) tree(synset *Synset, relationshipTypes []int, maxDepth int, onPath map[synsetKey]bool) *SynsetTree {
+    node := &SynsetTree { synset, []*SynsetTree{} }
+    if maxDepth == 0 {
+        return node
+    }
+    k := getSynsetKey(synset)
+    onPath[k] = true
+    defer delete(onPath, k)
+
+    for _, related := range wn.Related(synset, relationshipTypes) {
+        if onPath[getSynsetKey(related)] {
+            continue
+        }
+        node.Children = append(node.Children, wn.tree(related, relationshipTypes, maxDepth - 1, onPath))
+    }
+    return node
+}
+
+func containsInt(list []int, n int) bool {
+    for _, i := range list {
+        if i == n {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,51 @@
+package gown
+
+import (
+    "testing"
+)
+
+func TestTraversal(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+    dog := wn.LookupWithPartOfSpeechAndSense("dog", POS_NOUN, 1).GetSynsetPtr()
+    entity := wn.LookupWithPartOfSpeechAndSense("entity", POS_NOUN, 1).GetSynsetPtr()
+
+    closure := wn.Closure(dog, HYPERNYM_RELATIONSHIPS, -1)
+    if len(closure) == 0 || closure[len(closure) - 1].SynsetOffset != entity.SynsetOffset {
+        t.Errorf("expected the hypernym closure of dog to end with entity: %v", closure)
+    }
+    direct := wn.Closure(dog, HYPERNYM_RELATIONSHIPS, 1)
+    if len(direct) == 0 || len(direct) != len(wn.Related(dog, HYPERNYM_RELATIONSHIPS)) {
+        t.Errorf("expected the direct hypernyms of dog: %v", direct)
+    }
+
+    paths := wn.PathsToRoot(dog, HYPERNYM_RELATIONSHIPS)
+    if len(paths) == 0 {
+        t.Fatalf("no paths from dog to the root")
+    }
+    for _, path := range paths {
+        if path[0].SynsetOffset != entity.SynsetOffset || path[len(path) - 1].SynsetOffset != dog.SynsetOffset {
+            t.Errorf("expected a path from entity to dog: %v", path)
+        }
+    }
+
+    tree := wn.Tree(dog, HYPERNYM_RELATIONSHIPS, 1)
+    if tree.Synset != dog || len(tree.Children) != len(direct) {
+        t.Errorf("unexpected hypernym tree of dog: %v", tree)
+    }
+    for _, child := range tree.Children {
+        if len(child.Children) != 0 {
+            t.Errorf("tree is deeper than 1")
+        }
+    }
+
+    // antonyms point at each other
+    wet := wn.LookupWithPartOfSpeechAndSense("wet", POS_ADJECTIVE, 1).GetSynsetPtr()
+    antonyms := wn.Closure(wet, []int { ANTONYM_RELATIONSHIP }, -1)
+    if len(antonyms) != 1 || antonyms[0].Words[0] != "dry" {
+        t.Errorf("expected dry as the only antonym of wet: %v", antonyms)
+    }
+    antonymPaths := wn.PathsToRoot(wet, []int { ANTONYM_RELATIONSHIP })
+    if len(antonymPaths) != 1 || len(antonymPaths[0]) != 2 {
+        t.Errorf("expected the cycle between wet and dry to be cut: %v", antonymPaths)
+    }
+}
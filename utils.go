@@ -115,6 +115,7 @@ var (
         INSTANCE_HYPERNYM_RELATIONSHIP: "hypernym-instance",
         HYPONYM_RELATIONSHIP: "hyponym",
         INSTANCE_HYPONYM_RELATIONSHIP: "hyponym-instance",
+        TROPONYM_RELATIONSHIP: "troponym",
         MEMBER_HOLONYM_RELATIONSHIP: "holonym-member",
         SUBSTANCE_HOLONYM_RELATIONSHIP: "holonym-substance",
         PART_HOLONYM_RELATIONSHIP: "holonym-part",
@@ -131,12 +132,53 @@ var (
         DOMAIN_OF_SYNSET_USAGE_RELATIONSHIP: "domain-usage",
         MEMBER_OF_THIS_DOMAIN_USAGE_RELATIONSHIP: "domain-usage-member",
         ENTAILMENT_RELATIONSHIP: "entailment",
+        ENTAILED_BY_RELATIONSHIP: "entailed-by",
         CAUSAL_RELATIONSHIP: "causal",
+        CAUSED_BY_RELATIONSHIP: "caused-by",
         ALSO_SEE_RELATIONSHIP: "also-see",
         VERB_GROUP_RELATIONSHIP: "verb-group",
         SIMILAR_TO_RELATIONSHIP: "similar-to",
         PARTICIPLE_OF_VERB_RELATIONSHIP: "verb-participle",
+        VERB_PARTICIPLE_RELATIONSHIP: "participle",
         PERTAINYM_RELATIONSHIP: "pertainym",
+        PERTAINYM_OF_RELATIONSHIP: "pertainym-of",
+    }
+
+    // the relationship type of the edge pointing back for each type of edge.
+    // The inverse of a verb hypernym is a TROPONYM_RELATIONSHIP.
+    // DOMAIN_OF_SYNSET has no inverse.
+    RELATIONSHIP_INVERSES = map[int]int {
+        ANTONYM_RELATIONSHIP: ANTONYM_RELATIONSHIP,
+        HYPERNYM_RELATIONSHIP: HYPONYM_RELATIONSHIP,
+        INSTANCE_HYPERNYM_RELATIONSHIP: INSTANCE_HYPONYM_RELATIONSHIP,
+        HYPONYM_RELATIONSHIP: HYPERNYM_RELATIONSHIP,
+        INSTANCE_HYPONYM_RELATIONSHIP: INSTANCE_HYPERNYM_RELATIONSHIP,
+        TROPONYM_RELATIONSHIP: HYPERNYM_RELATIONSHIP,
+        MEMBER_HOLONYM_RELATIONSHIP: MEMBER_MERONYM_RELATIONSHIP,
+        SUBSTANCE_HOLONYM_RELATIONSHIP: SUBSTANCE_MERONYM_RELATIONSHIP,
+        PART_HOLONYM_RELATIONSHIP: PART_MERONYM_RELATIONSHIP,
+        MEMBER_MERONYM_RELATIONSHIP: MEMBER_HOLONYM_RELATIONSHIP,
+        SUBSTANCE_MERONYM_RELATIONSHIP: SUBSTANCE_HOLONYM_RELATIONSHIP,
+        PART_MERONYM_RELATIONSHIP: PART_HOLONYM_RELATIONSHIP,
+        ATTRIBUTE_RELATIONSHIP: ATTRIBUTE_RELATIONSHIP,
+        DERIVATIONALLY_RELATED_FORM_RELATIONSHIP: DERIVATIONALLY_RELATED_FORM_RELATIONSHIP,
+        DOMAIN_OF_SYNSET_TOPIC_RELATIONSHIP: MEMBER_OF_THIS_DOMAIN_TOPIC_RELATIONSHIP,
+        MEMBER_OF_THIS_DOMAIN_TOPIC_RELATIONSHIP: DOMAIN_OF_SYNSET_TOPIC_RELATIONSHIP,
+        DOMAIN_OF_SYNSET_REGION_RELATIONSHIP: MEMBER_OF_THIS_DOMAIN_REGION_RELATIONSHIP,
+        MEMBER_OF_THIS_DOMAIN_REGION_RELATIONSHIP: DOMAIN_OF_SYNSET_REGION_RELATIONSHIP,
+        DOMAIN_OF_SYNSET_USAGE_RELATIONSHIP: MEMBER_OF_THIS_DOMAIN_USAGE_RELATIONSHIP,
+        MEMBER_OF_THIS_DOMAIN_USAGE_RELATIONSHIP: DOMAIN_OF_SYNSET_USAGE_RELATIONSHIP,
+        ENTAILMENT_RELATIONSHIP: ENTAILED_BY_RELATIONSHIP,
+        ENTAILED_BY_RELATIONSHIP: ENTAILMENT_RELATIONSHIP,
+        CAUSAL_RELATIONSHIP: CAUSED_BY_RELATIONSHIP,
+        CAUSED_BY_RELATIONSHIP: CAUSAL_RELATIONSHIP,
+        ALSO_SEE_RELATIONSHIP: ALSO_SEE_RELATIONSHIP,
+        VERB_GROUP_RELATIONSHIP: VERB_GROUP_RELATIONSHIP,
+        SIMILAR_TO_RELATIONSHIP: SIMILAR_TO_RELATIONSHIP,
+        PARTICIPLE_OF_VERB_RELATIONSHIP: VERB_PARTICIPLE_RELATIONSHIP,
+        VERB_PARTICIPLE_RELATIONSHIP: PARTICIPLE_OF_VERB_RELATIONSHIP,
+        PERTAINYM_RELATIONSHIP: PERTAINYM_OF_RELATIONSHIP,
+        PERTAINYM_OF_RELATIONSHIP: PERTAINYM_RELATIONSHIP,
     }
 
     PART_OF_SPEECH_ID_TO_STRING = []string {
@@ -148,6 +190,47 @@ var (
       "adj_sat",
     }
 
+    // generic sentence frames for verbs, indexed by frame number (see
+    // wninput(5WN)). "----" stands in for the verb.
+    VERB_FRAME_STRINGS = []string {
+        "",
+        "Something ----s",
+        "Somebody ----s",
+        "It is ----ing",
+        "Something is ----ing PP",
+        "Something ----s something Adjective/Noun",
+        "Something ----s Adjective/Noun",
+        "Somebody ----s Adjective",
+        "Somebody ----s something",
+        "Somebody ----s somebody",
+        "Something ----s somebody",
+        "Something ----s something",
+        "Something ----s to somebody",
+        "Somebody ----s on something",
+        "Somebody ----s somebody something",
+        "Somebody ----s something to somebody",
+        "Somebody ----s something from somebody",
+        "Somebody ----s somebody with something",
+        "Somebody ----s somebody of something",
+        "Somebody ----s something on somebody",
+        "Somebody ----s somebody PP",
+        "Somebody ----s something PP",
+        "Somebody ----s PP",
+        "Somebody's (body part) ----s",
+        "Somebody ----s somebody to INFINITIVE",
+        "Somebody ----s somebody INFINITIVE",
+        "Somebody ----s that CLAUSE",
+        "Somebody ----s to somebody",
+        "Somebody ----s to INFINITIVE",
+        "Somebody ----s whether INFINITIVE",
+        "Somebody ----s somebody into V-ing something",
+        "Somebody ----s something with something",
+        "Somebody ----s INFINITIVE",
+        "Somebody ----s VERB-ing",
+        "It ----s that CLAUSE",
+        "Something ----s INFINITIVE",
+    }
+
 )
 
 // syntactic category / part of speech
@@ -164,6 +247,11 @@ const HYPERNYM_RELATIONSHIP int = 20
 const INSTANCE_HYPERNYM_RELATIONSHIP int = 21
 const HYPONYM_RELATIONSHIP int = 30
 const INSTANCE_HYPONYM_RELATIONSHIP int = 31
+// the inverse of a verb hypernym, only added by AddInverseRelationships where
+// the database has no "~" pointer back. Those pointers stay
+// HYPONYM_RELATIONSHIPs, so follow both types (or HYPONYM_RELATIONSHIPS) to
+// find every troponym of a verb.
+const TROPONYM_RELATIONSHIP int = 32
 const MEMBER_HOLONYM_RELATIONSHIP int = 40
 const SUBSTANCE_HOLONYM_RELATIONSHIP int = 41
 const PART_HOLONYM_RELATIONSHIP int = 42
@@ -180,12 +268,16 @@ const MEMBER_OF_THIS_DOMAIN_REGION_RELATIONSHIP int = 101
 const DOMAIN_OF_SYNSET_USAGE_RELATIONSHIP int = 110
 const MEMBER_OF_THIS_DOMAIN_USAGE_RELATIONSHIP int = 111
 const ENTAILMENT_RELATIONSHIP int = 120
+const ENTAILED_BY_RELATIONSHIP int = 121    // only added by AddInverseRelationships
 const CAUSAL_RELATIONSHIP int = 130
+const CAUSED_BY_RELATIONSHIP int = 131      // only added by AddInverseRelationships
 const ALSO_SEE_RELATIONSHIP int = 140
 const VERB_GROUP_RELATIONSHIP int = 150
 const SIMILAR_TO_RELATIONSHIP int = 160
 const PARTICIPLE_OF_VERB_RELATIONSHIP int = 170
+const VERB_PARTICIPLE_RELATIONSHIP int = 171 // only added by AddInverseRelationships
 const PERTAINYM_RELATIONSHIP int = 180
+const PERTAINYM_OF_RELATIONSHIP int = 181   // only added by AddInverseRelationships
 
 // syntactic markers for adjectives
 const SYNTACTIC_MARKER_NOT_APPLICABLE int = 0
@@ -197,13 +289,30 @@ const SYNTACTIC_MARKER_IMMEDIATELY_POSTNOMIAL_POSITION int = 3
      return fmt.Sprintf("%s%02d", lemma, sense_id)
  }
 
+var SYNTACTIC_MARKER_STRINGS = map[string]int {
+    "(p)": SYNTACTIC_MARKER_PREDICATE_POSITION,
+    "(a)": SYNTACTIC_MARKER_PRENOMINAL_POSITION,
+    "(ip)": SYNTACTIC_MARKER_IMMEDIATELY_POSTNOMIAL_POSITION,
+}
+
 func readStoredLemma(s string) string {
+    lemma, _ := readStoredWord(s)
+    return lemma
+}
+
+// Like readStoredLemma, but also returns the syntactic marker of an
+// adjective. (e.g. "elect(ip)" is "elect" and
+// SYNTACTIC_MARKER_IMMEDIATELY_POSTNOMIAL_POSITION)
+func readStoredWord(s string) (string, int) {
     spaced := strings.Replace(s, "_", " ", -1)
-    if spaced[len(spaced) - 1] == ')' {
-        return spaced[0:len(spaced)-3]
-    } else {
-        return spaced
+    markerIndex := strings.LastIndex(spaced, "(")
+    if markerIndex > 0 {
+        marker, isMarker := SYNTACTIC_MARKER_STRINGS[spaced[markerIndex:]]
+        if isMarker {
+            return spaced[:markerIndex], marker
+        }
     }
+    return spaced, SYNTACTIC_MARKER_NOT_APPLICABLE
 }
 
 func writeStoredLemma(s string) string {
@@ -226,3 +335,13 @@ func oneCharPosTagToPosId(tag string) int {
         return POS_UNSUPPORTED
     }
 }
+
+// appends s to list if it isn't already in it
+func appendUnique(list []string, s string) []string {
+    for _, existing := range list {
+        if existing == s {
+            return list
+        }
+    }
+    return append(list, s)
+}
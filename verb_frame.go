@@ -0,0 +1,208 @@
+package gown
+
+import (
+    "fmt"
+    "io"
+    "io/fs"
+    "strconv"
+    "strings"
+)
+
+/*
+From wninput(5WN) and wndb(5WN):
+
+Each verb synset contains a list of generic sentence frames illustrating the
+types of simple sentences in which the verbs in the synset can be used. The
+frame templates are listed in verb.Framestext. In addition, some verb senses
+have one or more example sentences in sents.vrb, and sentidx.vrb maps the
+sense keys of those verbs to the sentence numbers that apply to them.
+*/
+
+// Loads the verb frame templates and, when present, the example sentences
+// from sents.vrb and sentidx.vrb. verb.Framestext is optional; the generic
+// frames in VERB_FRAME_STRINGS are used if it is missing.
+func (wn *WN) loadVerbFrames(fsys fs.FS) error {
+    var err error = nil
+
+    wn.verbFrameStrings = VERB_FRAME_STRINGS
+    if dictFileExists(fsys, "verb.Framestext") {
+        wn.verbFrameStrings, err = loadVerbFrameText(fsys, "verb.Framestext")
+        if err != nil {
+            return err
+        }
+    }
+
+    if dictFileExists(fsys, "sents.vrb") && dictFileExists(fsys, "sentidx.vrb") {
+        wn.verbSentences, err = loadNumberedLines(fsys, "sents.vrb")
+        if err != nil {
+            return err
+        }
+        wn.verbSentenceIndex, err = loadVerbSentenceIndex(fsys, "sentidx.vrb")
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// Reads verb.Framestext. The format is:
+// f_num  frame_text
+func loadVerbFrameText(fsys fs.FS, frameTextFilename string) ([]string, error) {
+    numbered, err := loadNumberedLines(fsys, frameTextFilename)
+    if err != nil {
+        return nil, err
+    }
+    frames := make([]string, len(VERB_FRAME_STRINGS))
+    copy(frames, VERB_FRAME_STRINGS)
+    for frameNumber, text := range numbered {
+        for frameNumber >= len(frames) {
+            frames = append(frames, "")
+        }
+        frames[frameNumber] = text
+    }
+    return frames, nil
+}
+
+// Reads a file of lines starting with a number followed by text, such as
+// sents.vrb or verb.Framestext
+func loadNumberedLines(fsys fs.FS, filename string) (map[int]string, error) {
+    infile, err := openDictFile(fsys, filename)
+    if err != nil {
+        return nil, fmt.Errorf("can't open %s: %v", filename, err)
+    }
+    defer infile.Close()
+
+    lines := map[int]string{}
+    lr := newLineReader(infile, filename)
+    for {
+        line, err := lr.next()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, err
+        }
+        fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+        if len(fields) < 2 {
+            continue
+        }
+        number, err := strconv.Atoi(fields[0])
+        if err != nil {
+            return nil, lr.fieldError("number", fields[0])
+        }
+        lines[number] = fields[1]
+    }
+
+    return lines, nil
+}
+
+// Reads sentidx.vrb. The format is:
+// sense_key  sentence_number[,sentence_number...]
+func loadVerbSentenceIndex(fsys fs.FS, sentenceIndexFilename string) (map[string][]int, error) {
+    infile, err := openDictFile(fsys, sentenceIndexFilename)
+    if err != nil {
+        return nil, fmt.Errorf("can't open %s: %v", sentenceIndexFilename, err)
+    }
+    defer infile.Close()
+
+    index := map[string][]int{}
+    lr := newLineReader(infile, sentenceIndexFilename)
+    for {
+        line, err := lr.next()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, err
+        }
+        f := lr.fields(line)
+        if !f.more() {
+            continue
+        }
+        senseKey := f.str("sense_key")
+        numbers := f.str("sentence_numbers")
+        if f.err != nil {
+            return nil, f.err
+        }
+        sentences := []int{}
+        for _, number := range strings.Split(numbers, ",") {
+            n, err := strconv.Atoi(number)
+            if err != nil {
+                return nil, lr.fieldError("sentence_numbers", numbers)
+            }
+            sentences = append(sentences, n)
+        }
+        index[senseKey] = sentences
+    }
+
+    return index, nil
+}
+
+// Returns the generic sentence frames that apply to a word of a verb
+// synset, with the word substituted for the verb. (e.g. "Somebody gives
+// somebody something") wordIndex is the zero based index into
+// synset.Words.
+func (wn *WN) GetVerbFrames(synset *Synset, wordIndex int) []string {
+    if synset == nil || wordIndex < 0 || wordIndex >= len(synset.Words) {
+        return nil
+    }
+    lemma := synset.Words[wordIndex]
+    ret := []string{}
+    for _, frame := range synset.Frames {
+        if frame.WordNumber != 0 && frame.WordNumber != wordIndex + 1 {
+            continue
+        }
+        if frame.FrameNumber <= 0 || frame.FrameNumber >= len(wn.verbFrameStrings) {
+            continue
+        }
+        ret = append(ret, wn.renderVerbFrame(wn.verbFrameStrings[frame.FrameNumber], lemma))
+    }
+    return ret
+}
+
+// Returns the example sentences from sents.vrb for a word of a verb synset,
+// with the word substituted in. wordIndex is the zero based index into
+// synset.Words. Returns nil if the sentence files weren't loaded.
+func (wn *WN) GetVerbFrameSentences(synset *Synset, wordIndex int) []string {
+    if wn.verbSentenceIndex == nil || synset == nil || wordIndex < 0 || wordIndex >= len(synset.Words) {
+        return nil
+    }
+    lemma := synset.Words[wordIndex]
+    senseKey := wn.senseKey(synset, wordIndex).String()
+    ret := []string{}
+    for _, sentenceNumber := range wn.verbSentenceIndex[senseKey] {
+        sentence, exists := wn.verbSentences[sentenceNumber]
+        if exists {
+            ret = append(ret, strings.Replace(sentence, "%s", lemma, 1))
+        }
+    }
+    return ret
+}
+
+// Substitutes the lemma for the "----" placeholder of a frame template,
+// inflecting the first word of the lemma for "----s" and "----ing".
+func (wn *WN) renderVerbFrame(frame string, lemma string) string {
+    placeholderIndex := strings.Index(frame, "----")
+    if placeholderIndex < 0 {
+        return frame
+    }
+    rest := frame[placeholderIndex + 4:]
+    suffix := ""
+    if strings.HasPrefix(rest, "ing") {
+        suffix = "ing"
+    } else if strings.HasPrefix(rest, "s") {
+        suffix = "s"
+    }
+    rest = rest[len(suffix):]
+
+    verb := lemma
+    switch suffix {
+    case "s":
+        verb = wn.inflectAs(lemma, POS_VERB, INFLECTION_THIRD_PERSON_SINGULAR)
+    case "ing":
+        verb = wn.inflectAs(lemma, POS_VERB, INFLECTION_PRESENT_PARTICIPLE)
+    }
+
+    return frame[:placeholderIndex] + verb + rest
+}
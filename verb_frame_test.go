@@ -0,0 +1,95 @@
+package gown
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestRenderVerbFrame(t *testing.T) {
+    frames := []string {
+        "Somebody ----s somebody something",
+        "It is ----ing",
+        "Somebody ----s something",
+        "Something ----s",
+    }
+    lemmas := []string {
+        "give",
+        "snow",
+        "give up",
+        "fly",
+    }
+    expecteds := []string {
+        "Somebody gives somebody something",
+        "It is snowing",
+        "Somebody gives up something",
+        "Something flies",
+    }
+
+    wn := &WN{}
+    for i, frame := range frames {
+        actual := wn.renderVerbFrame(frame, lemmas[i])
+        if actual != expecteds[i] {
+            t.Errorf("for %q/%q expected %q but got %q\n", frame, lemmas[i], expecteds[i], actual)
+        }
+    }
+}
+
+func TestGetVerbFrames(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+
+    give := wn.LookupWithPartOfSpeechAndSense("give", POS_VERB, 1)
+    if give == nil {
+        t.Fatalf("\"give\" not found in sense index. Not loaded correctly?")
+    }
+    synset := give.GetSynsetPtr()
+    if synset == nil || len(synset.Frames) == 0 {
+        t.Fatalf("expected verb frames for %v", synset)
+    }
+    expected := []string {
+        "Somebody gives somebody something",
+        "Somebody gives something",
+        "Somebody gives something PP",
+    }
+    if actual := wn.GetVerbFrames(synset, 0); !reflect.DeepEqual(actual, expected) {
+        t.Errorf("expected the frames of \"give\" to be %q, but got %q", expected, actual)
+    }
+    for i, _ := range synset.Words {
+        for _, frame := range wn.GetVerbFrames(synset, i) {
+            if strings.Contains(frame, "----") {
+                t.Errorf("frame %q was not rendered", frame)
+            }
+        }
+    }
+
+    for synset := range wn.Iter() {
+        if synset.PartOfSpeech != POS_VERB && len(synset.Frames) > 0 {
+            t.Errorf("unexpected frames for %v", synset)
+        }
+        for _, frame := range synset.Frames {
+            if frame.FrameNumber <= 0 || frame.FrameNumber >= len(VERB_FRAME_STRINGS) {
+                t.Errorf("bad frame number %d for %v", frame.FrameNumber, synset.Words)
+            }
+        }
+    }
+}
+
+func TestGetVerbFrameSentences(t *testing.T) {
+    wn := loadTestWordNet(t, LoadOptions{})
+
+    give := wn.LookupWithPartOfSpeechAndSense("give", POS_VERB, 1).GetSynsetPtr()
+    expected := []string { "The banks give the check" }
+    if actual := wn.GetVerbFrameSentences(give, 0); !reflect.DeepEqual(actual, expected) {
+        t.Errorf("expected the sentences of \"give\" to be %q, but got %q", expected, actual)
+    }
+    if actual := wn.GetVerbFrameSentences(give, 1); actual != nil {
+        t.Errorf("expected no sentences for a word past the end, but got %q", actual)
+    }
+
+    // without sents.vrb and sentidx.vrb
+    wn.verbSentenceIndex = nil
+    wn.verbSentences = nil
+    if actual := wn.GetVerbFrameSentences(give, 0); actual != nil {
+        t.Errorf("expected no sentences without the sentence files, but got %q", actual)
+    }
+}
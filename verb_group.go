@@ -0,0 +1,121 @@
+package gown
+
+import (
+    "sort"
+)
+
+/*
+Verb groups collect verb senses that are similar in meaning. The "$"
+pointers in data.verb don't connect every member of a group to every other,
+so which members are reachable depends on where you start. VerbGroup follows
+them transitively in both directions.
+*/
+
+// Returns the other members of the verb group of a verb synset, ordered by
+// synset offset. Returns an empty slice if the synset isn't in a group.
+func (wn *WN) VerbGroup(synset *Synset) []*Synset {
+    ret := []*Synset{}
+    if synset == nil || synset.PartOfSpeech != POS_VERB {
+        return ret
+    }
+    for _, offset := range wn.getVerbGroups()[synset.SynsetOffset] {
+        if offset == synset.SynsetOffset {
+            continue
+        }
+        member := wn.GetSynset(POS_VERB, offset)
+        if member != nil {
+            ret = append(ret, member)
+        }
+    }
+    return ret
+}
+
+// Adds a VERB_GROUP_RELATIONSHIP from every member of each verb group to
+// every other member that it doesn't already point to.
+func (wn *WN) ConnectVerbGroups() {
+    existing := map[edgeKey]bool{}
+    for synset := range wn.Iter() {
+        if synset.PartOfSpeech != POS_VERB {
+            continue
+        }
+        from := getSynsetKey(synset)
+        for _, edge := range synset.Relationships {
+            if edge.RelationshipType == VERB_GROUP_RELATIONSHIP {
+                // lexical pointers between words count too
+                edge.SourceWordNumber = 0
+                edge.TargetWordNumber = 0
+                existing[getEdgeKey(from, edge)] = true
+            }
+        }
+    }
+
+    edges := map[synsetKey][]RelationshipEdge{}
+    for offset, group := range wn.getVerbGroups() {
+        from := synsetKey { POS_VERB, offset }
+        for _, memberOffset := range group {
+            if memberOffset == offset {
+                continue
+            }
+            edge := RelationshipEdge {
+                RelationshipType: VERB_GROUP_RELATIONSHIP,
+                SynsetOffset: memberOffset,
+                PartOfSpeech: POS_VERB,
+            }
+            if !existing[getEdgeKey(from, edge)] {
+                edges[from] = append(edges[from], edge)
+            }
+        }
+    }
+    wn.addRelationships(edges)
+}
+
+// Returns the members of each verb group, including the synset itself,
+// indexed by the offsets of the members. Computed once.
+func (wn *WN) getVerbGroups() map[int][]int {
+    wn.verbGroupsLock.Lock()
+    defer wn.verbGroupsLock.Unlock()
+    if wn.verbGroups != nil {
+        return wn.verbGroups
+    }
+
+    // union find over the verb group pointers
+    parents := map[int]int{}
+    var find func(offset int) int
+    find = func(offset int) int {
+        parent, exists := parents[offset]
+        if !exists || parent == offset {
+            return offset
+        }
+        root := find(parent)
+        parents[offset] = root
+        return root
+    }
+    for synset := range wn.Iter() {
+        if synset.PartOfSpeech != POS_VERB {
+            continue
+        }
+        for _, edge := range synset.Relationships {
+            if edge.RelationshipType != VERB_GROUP_RELATIONSHIP || edge.PartOfSpeech != POS_VERB {
+                continue
+            }
+            root1 := find(synset.SynsetOffset)
+            root2 := find(edge.SynsetOffset)
+            parents[root1] = root1
+            parents[root2] = root1
+        }
+    }
+
+    members := map[int][]int{}
+    for offset, _ := range parents {
+        root := find(offset)
+        members[root] = append(members[root], offset)
+    }
+    wn.verbGroups = map[int][]int{}
+    for _, group := range members {
+        sort.Ints(group)
+        for _, offset := range group {
+            wn.verbGroups[offset] = group
+        }
+    }
+    return wn.verbGroups
+}
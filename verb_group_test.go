@@ -0,0 +1,33 @@
+package gown
+
+import (
+    "testing"
+)
+
+func TestVerbGroup(t *testing.T) {
+    forEachLoadMode(t, LoadOptions { ConnectVerbGroups: true }, func(t *testing.T, wn *WN) {
+        groups := 0
+        for synset := range wn.Iter() {
+            if synset.PartOfSpeech != POS_VERB {
+                continue
+            }
+            group := wn.VerbGroup(synset)
+            if len(group) > 0 {
+                groups++
+            }
+            for _, member := range group {
+                // membership is the same from every member
+                if len(wn.VerbGroup(member)) != len(group) {
+                    t.Fatalf("groups of %v and %v differ", synset.Words, member.Words)
+                }
+                // and every member points to every other
+                if countRelationships(synset, VERB_GROUP_RELATIONSHIP, member) == 0 {
+                    t.Fatalf("%v doesn't point to %v", synset.Words, member.Words)
+                }
+            }
+        }
+        if groups == 0 {
+            t.Errorf("no verb groups found")
+        }
+    })
+}